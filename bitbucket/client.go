@@ -0,0 +1,138 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const BaseURL = "https://api.bitbucket.org/2.0"
+
+//go:generate counterfeiter . Client
+
+// Client speaks to the subset of the Bitbucket Cloud REST API (v2.0) this
+// package's verifiers need. It mirrors github.Client: a thin, directly
+// testable seam between the verifiers (which only care about the decoded
+// result) and the raw HTTP/pagination/auth details of talking to Bitbucket.
+type Client interface {
+	CurrentUser(httpClient *http.Client) (string, error)
+	WorkspaceMembers(httpClient *http.Client, workspace string) ([]WorkspaceMember, error)
+	WorkspacePermissions(httpClient *http.Client, workspace string) ([]WorkspacePermission, error)
+	RepositoryPermissions(httpClient *http.Client) ([]RepositoryPermission, error)
+}
+
+// WorkspaceMember is an entry from /2.0/workspaces/{slug}/members, which
+// only confirms membership - it carries no per-member role.
+type WorkspaceMember struct {
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+}
+
+// WorkspacePermission is an entry from /2.0/workspaces/{slug}/permissions,
+// the endpoint that actually carries a member's role ("member", "collaborator",
+// or "admin"). Listing it requires the caller to be a workspace admin.
+type WorkspacePermission struct {
+	Permission string `json:"permission"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+}
+
+type RepositoryPermission struct {
+	Permission string `json:"permission"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type APIClient struct{}
+
+func (APIClient) CurrentUser(httpClient *http.Client) (string, error) {
+	var user struct {
+		Username string `json:"username"`
+	}
+
+	if err := get(httpClient, BaseURL+"/user", &user); err != nil {
+		return "", err
+	}
+
+	return user.Username, nil
+}
+
+func (APIClient) WorkspaceMembers(httpClient *http.Client, workspace string) ([]WorkspaceMember, error) {
+	var members []WorkspaceMember
+
+	url := fmt.Sprintf("%s/workspaces/%s/members", BaseURL, workspace)
+	for url != "" {
+		var page struct {
+			Values []WorkspaceMember `json:"values"`
+			Next   string            `json:"next"`
+		}
+
+		if err := get(httpClient, url, &page); err != nil {
+			return nil, err
+		}
+
+		members = append(members, page.Values...)
+		url = page.Next
+	}
+
+	return members, nil
+}
+
+func (APIClient) WorkspacePermissions(httpClient *http.Client, workspace string) ([]WorkspacePermission, error) {
+	var permissions []WorkspacePermission
+
+	url := fmt.Sprintf("%s/workspaces/%s/permissions", BaseURL, workspace)
+	for url != "" {
+		var page struct {
+			Values []WorkspacePermission `json:"values"`
+			Next   string                `json:"next"`
+		}
+
+		if err := get(httpClient, url, &page); err != nil {
+			return nil, err
+		}
+
+		permissions = append(permissions, page.Values...)
+		url = page.Next
+	}
+
+	return permissions, nil
+}
+
+func (APIClient) RepositoryPermissions(httpClient *http.Client) ([]RepositoryPermission, error) {
+	var permissions []RepositoryPermission
+
+	url := BaseURL + "/user/permissions/repositories"
+	for url != "" {
+		var page struct {
+			Values []RepositoryPermission `json:"values"`
+			Next   string                 `json:"next"`
+		}
+
+		if err := get(httpClient, url, &page); err != nil {
+			return nil, err
+		}
+
+		permissions = append(permissions, page.Values...)
+		url = page.Next
+	}
+
+	return permissions, nil
+}
+
+func get(httpClient *http.Client, url string, out interface{}) error {
+	response, err := httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response code from Bitbucket API: %d", response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(out)
+}
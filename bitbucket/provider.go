@@ -0,0 +1,24 @@
+package bitbucket
+
+import (
+	"golang.org/x/oauth2"
+
+	"github.com/concourse/atc/auth/verifier"
+)
+
+const ProviderName = "bitbucket"
+const DisplayName = "Bitbucket"
+
+var Endpoint = oauth2.Endpoint{
+	AuthURL:  "https://bitbucket.org/site/oauth2/authorize",
+	TokenURL: "https://bitbucket.org/site/oauth2/access_token",
+}
+
+type Provider struct {
+	*oauth2.Config
+	verifier.Verifier
+}
+
+func (Provider) DisplayName() string {
+	return DisplayName
+}
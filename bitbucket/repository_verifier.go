@@ -0,0 +1,48 @@
+package bitbucket
+
+import (
+	"net/http"
+
+	"github.com/concourse/atc/auth/verifier"
+	"github.com/pivotal-golang/lager"
+)
+
+// RepositoryVerifier checks whether the current user has any access
+// (read, write, or admin) to one of the given repositories, identified by
+// their "workspace/repo_slug" full name.
+type RepositoryVerifier struct {
+	repositories []string
+	client       Client
+}
+
+func NewRepositoryVerifier(
+	repositories []string,
+	client Client,
+) verifier.Verifier {
+	return RepositoryVerifier{
+		repositories: repositories,
+		client:       client,
+	}
+}
+
+func (v RepositoryVerifier) Verify(logger lager.Logger, httpClient *http.Client) (bool, error) {
+	permissions, err := v.client.RepositoryPermissions(httpClient)
+	if err != nil {
+		logger.Error("failed-to-get-repository-permissions", err)
+		return false, err
+	}
+
+	for _, permission := range permissions {
+		for _, repository := range v.repositories {
+			if permission.Repository.FullName == repository {
+				return true, nil
+			}
+		}
+	}
+
+	logger.Info("not-permitted-on-repositories", lager.Data{
+		"want": v.repositories,
+	})
+
+	return false, nil
+}
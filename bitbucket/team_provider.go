@@ -0,0 +1,93 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/jessevdk/go-flags"
+	"golang.org/x/oauth2"
+
+	"github.com/concourse/atc/auth/provider"
+	"github.com/concourse/atc/auth/verifier"
+)
+
+func init() {
+	provider.Register(ProviderName, TeamProvider{})
+}
+
+type AuthConfig struct {
+	ClientID     string   `json:"client_id"     long:"client-id"     description:"Application client ID for enabling Bitbucket OAuth."`
+	ClientSecret string   `json:"client_secret" long:"client-secret" description:"Application client secret for enabling Bitbucket OAuth."`
+	Users        []string `json:"users,omitempty"        long:"auth-user"      description:"Bitbucket username allowed to log in."`
+	Teams        []string `json:"teams,omitempty"        long:"auth-team"      description:"Bitbucket workspace (team) slug whose members are allowed to log in."`
+	TeamRole     string   `json:"team_role,omitempty"    long:"auth-team-role" description:"Workspace permission (e.g. 'member' or 'admin') a --bitbucket-auth-team member must hold. Defaults to any permission. Requires the OAuth app's user to be a workspace admin, since Bitbucket only exposes per-member roles to admins."`
+	Repositories []string `json:"repositories,omitempty" long:"auth-repository" description:"'workspace/repo_slug' a user must have access to, to log in."`
+}
+
+func (config *AuthConfig) IsConfigured() bool {
+	return config.ClientID != "" || config.ClientSecret != ""
+}
+
+func (config *AuthConfig) Validate() error {
+	var errs *multierror.Error
+
+	if config.ClientID == "" || config.ClientSecret == "" {
+		errs = multierror.Append(errs, errors.New("must specify --bitbucket-client-id and --bitbucket-client-secret to use Bitbucket."))
+	}
+
+	if len(config.Users) == 0 && len(config.Teams) == 0 && len(config.Repositories) == 0 {
+		errs = multierror.Append(errs, errors.New("must specify --bitbucket-auth-user, --bitbucket-auth-team or --bitbucket-auth-repository to use Bitbucket."))
+	}
+
+	return errs.ErrorOrNil()
+}
+
+type TeamProvider struct{}
+
+func (TeamProvider) AddAuthGroup(group *flags.Group) provider.AuthConfig {
+	authFlags := &AuthConfig{}
+
+	authGroup, err := group.AddGroup("Bitbucket Authentication", "", authFlags)
+	if err != nil {
+		panic(err)
+	}
+
+	authGroup.Namespace = "bitbucket"
+
+	return authFlags
+}
+
+func (TeamProvider) UnmarshalConfig(config *json.RawMessage) (provider.AuthConfig, error) {
+	authFlags := &AuthConfig{}
+	if config != nil {
+		if err := json.Unmarshal(*config, authFlags); err != nil {
+			return nil, err
+		}
+	}
+
+	return authFlags, nil
+}
+
+func (TeamProvider) ProviderConstructor(
+	config provider.AuthConfig,
+	redirectURL string,
+) (provider.Provider, bool) {
+	authConfig := config.(*AuthConfig)
+
+	client := APIClient{}
+
+	return Provider{
+		Config: &oauth2.Config{
+			ClientID:     authConfig.ClientID,
+			ClientSecret: authConfig.ClientSecret,
+			Endpoint:     Endpoint,
+			RedirectURL:  redirectURL,
+		},
+		Verifier: verifier.NewBasket(
+			NewUserVerifier(authConfig.Users, client),
+			NewTeamVerifier(authConfig.Teams, authConfig.TeamRole, client),
+			NewRepositoryVerifier(authConfig.Repositories, client),
+		),
+	}, true
+}
@@ -0,0 +1,111 @@
+package bitbucket
+
+import (
+	"net/http"
+
+	"github.com/concourse/atc/auth/verifier"
+	"github.com/pivotal-golang/lager"
+)
+
+// TeamVerifier checks whether the current user is a member of one of the
+// given Bitbucket workspaces (what the API still calls "teams" is now
+// surfaced as a workspace). When role is non-empty ("member" or "admin"),
+// membership must also carry that permission level, rather than any
+// membership being enough - this is checked against /permissions rather
+// than /members, since Bitbucket only surfaces a member's role on the
+// former (and only to a caller who is themselves a workspace admin).
+type TeamVerifier struct {
+	workspaces []string
+	role       string
+	client     Client
+}
+
+func NewTeamVerifier(
+	workspaces []string,
+	role string,
+	client Client,
+) verifier.Verifier {
+	return TeamVerifier{
+		workspaces: workspaces,
+		role:       role,
+		client:     client,
+	}
+}
+
+func (v TeamVerifier) Verify(logger lager.Logger, httpClient *http.Client) (bool, error) {
+	currentUser, err := v.client.CurrentUser(httpClient)
+	if err != nil {
+		logger.Error("failed-to-get-current-user", err)
+		return false, err
+	}
+
+	for _, workspace := range v.workspaces {
+		if v.role == "" {
+			member, err := v.isMember(logger, httpClient, workspace, currentUser)
+			if err != nil {
+				return false, err
+			}
+
+			if member {
+				return true, nil
+			}
+
+			continue
+		}
+
+		hasRole, err := v.hasRole(logger, httpClient, workspace, currentUser)
+		if err != nil {
+			return false, err
+		}
+
+		if hasRole {
+			return true, nil
+		}
+	}
+
+	logger.Info("not-in-workspaces", lager.Data{
+		"have":      currentUser,
+		"want":      v.workspaces,
+		"want-role": v.role,
+	})
+
+	return false, nil
+}
+
+// isMember answers plain membership (no role requested) via
+// /members, which every workspace member can list.
+func (v TeamVerifier) isMember(logger lager.Logger, httpClient *http.Client, workspace string, username string) (bool, error) {
+	members, err := v.client.WorkspaceMembers(httpClient, workspace)
+	if err != nil {
+		logger.Error("failed-to-get-workspace-members", err, lager.Data{"workspace": workspace})
+		return false, err
+	}
+
+	for _, member := range members {
+		if member.User.Username == username {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// hasRole answers a role-gated check via /permissions, the endpoint that
+// actually carries a member's role (members does not). This requires the
+// OAuth user to be a workspace admin; Bitbucket returns 403 otherwise, which
+// surfaces as an error here rather than a silent false.
+func (v TeamVerifier) hasRole(logger lager.Logger, httpClient *http.Client, workspace string, username string) (bool, error) {
+	permissions, err := v.client.WorkspacePermissions(httpClient, workspace)
+	if err != nil {
+		logger.Error("failed-to-get-workspace-permissions", err, lager.Data{"workspace": workspace})
+		return false, err
+	}
+
+	for _, permission := range permissions {
+		if permission.User.Username == username && permission.Permission == v.role {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
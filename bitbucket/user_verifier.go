@@ -0,0 +1,44 @@
+package bitbucket
+
+import (
+	"net/http"
+
+	"github.com/concourse/atc/auth/verifier"
+	"github.com/pivotal-golang/lager"
+)
+
+type UserVerifier struct {
+	users  []string
+	client Client
+}
+
+func NewUserVerifier(
+	users []string,
+	client Client,
+) verifier.Verifier {
+	return UserVerifier{
+		users:  users,
+		client: client,
+	}
+}
+
+func (v UserVerifier) Verify(logger lager.Logger, httpClient *http.Client) (bool, error) {
+	currentUser, err := v.client.CurrentUser(httpClient)
+	if err != nil {
+		logger.Error("failed-to-get-current-user", err)
+		return false, err
+	}
+
+	for _, user := range v.users {
+		if user == currentUser {
+			return true, nil
+		}
+	}
+
+	logger.Info("not-validated-user", lager.Data{
+		"have": currentUser,
+		"want": v.users,
+	})
+
+	return false, nil
+}
@@ -0,0 +1,48 @@
+package auth
+
+import "net/http"
+
+// defaultTeamName mirrors atc.DefaultTeamName: the team `fly login` puts you
+// on when you don't ask for one by name, and the only team basic auth (with
+// no team claim on its token) is ever allowed to act as.
+const defaultTeamName = "main"
+
+// CheckAuthorizationHandler protects handler behind the team found on the
+// request by WrapHandler: unauthenticated requests are rejected outright,
+// and authenticated requests must belong to the :team_name path param, or
+// have no team at all (in which case they're treated as the default team).
+func CheckAuthorizationHandler(
+	handler http.Handler,
+	rejector Rejector,
+) http.Handler {
+	return checkAuthorizationHandler{
+		handler:  handler,
+		rejector: rejector,
+	}
+}
+
+type checkAuthorizationHandler struct {
+	handler  http.Handler
+	rejector Rejector
+}
+
+func (h checkAuthorizationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !IsAuthenticated(r) {
+		h.rejector.Unauthorized(w, r)
+		return
+	}
+
+	requestedTeam := r.FormValue(":team_name")
+
+	teamName, _, _, found := GetTeam(r)
+	if !found {
+		teamName = defaultTeamName
+	}
+
+	if teamName != requestedTeam {
+		h.rejector.Forbidden(w, r)
+		return
+	}
+
+	h.handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// MaxCookieSize is the threshold, in bytes, above which a cookie value is
+// split across multiple Set-Cookie headers rather than sent as one. Most
+// browsers cap a single cookie around 4KB; once refresh tokens, ID tokens,
+// and group claims are folded into the signed ATC-Authorization JWT it
+// routinely blows past that, so anything bigger gets chunked.
+const MaxCookieSize = 3800
+
+func chunkedCookieName(name string, index int) string {
+	return fmt.Sprintf("%s_%d", name, index)
+}
+
+// WriteChunkedCookie sets value as the named cookie, splitting it across
+// name_0, name_1, ... when it exceeds MaxCookieSize. Values that fit in a
+// single cookie are written under name unchanged, so existing single-cookie
+// sessions (and anything reading them, e.g. fly) keep working.
+func WriteChunkedCookie(w http.ResponseWriter, name string, value string, path string, expires time.Time) {
+	if len(value) <= MaxCookieSize {
+		http.SetCookie(w, &http.Cookie{
+			Name:    name,
+			Value:   value,
+			Path:    path,
+			Expires: expires,
+		})
+		return
+	}
+
+	for i := 0; i*MaxCookieSize < len(value); i++ {
+		start := i * MaxCookieSize
+		end := start + MaxCookieSize
+		if end > len(value) {
+			end = len(value)
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:    chunkedCookieName(name, i),
+			Value:   value[start:end],
+			Path:    path,
+			Expires: expires,
+		})
+	}
+}
+
+// ReadChunkedCookie reassembles a cookie written by WriteChunkedCookie. It
+// first looks for a single cookie named name, falling back to name_0,
+// name_1, ... concatenated in order. It returns an error if neither form is
+// present.
+func ReadChunkedCookie(r *http.Request, name string) (string, error) {
+	if cookie, err := r.Cookie(name); err == nil {
+		return cookie.Value, nil
+	}
+
+	value := ""
+
+	for i := 0; ; i++ {
+		cookie, err := r.Cookie(chunkedCookieName(name, i))
+		if err != nil {
+			if i == 0 {
+				return "", http.ErrNoCookie
+			}
+
+			break
+		}
+
+		value += cookie.Value
+	}
+
+	return value, nil
+}
@@ -0,0 +1,31 @@
+package auth
+
+// ExtraJWTIssuer lets an operator trust bearer tokens signed by an external
+// OIDC issuer (a cloud provider's workload identity, a CI system's own
+// token service, ...) in addition to the ATC's own RSA signing key, so
+// those tokens can authenticate API requests without ever going through
+// the browser OAuth dance.
+type ExtraJWTIssuer struct {
+	IssuerURL string `json:"issuer_url" long:"issuer-url" description:"Issuer URL to fetch the JWKS and validate the 'iss' claim against."`
+	Audience  string `json:"audience"   long:"audience"   description:"Expected 'aud' claim on tokens issued by this issuer."`
+
+	// GroupsClaim is the claim whose values are mapped to Concourse team
+	// names for tokens from this issuer. Defaults to "groups". Tokens from
+	// an extra issuer are never treated as admin.
+	GroupsClaim string `json:"groups_claim" long:"groups-claim" description:"Claim to read team membership from. Defaults to 'groups'."`
+
+	// Teams is the allow-list of Concourse team names this issuer's tokens
+	// may authenticate as. The token's GroupsClaim value must contain one
+	// of these; the first match becomes the request's team.
+	Teams []string `json:"teams" long:"team" description:"Concourse team name a token's groups claim may assert. Repeatable."`
+}
+
+type ExtraJWTIssuers []ExtraJWTIssuer
+
+func (issuer ExtraJWTIssuer) groupsClaim() string {
+	if issuer.GroupsClaim == "" {
+		return "groups"
+	}
+
+	return issuer.GroupsClaim
+}
@@ -0,0 +1,63 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/concourse/atc/auth"
+)
+
+type FakeRejector struct {
+	UnauthorizedStub        func(w http.ResponseWriter, r *http.Request)
+	unauthorizedMutex       sync.RWMutex
+	unauthorizedArgsForCall []struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+
+	ForbiddenStub        func(w http.ResponseWriter, r *http.Request)
+	forbiddenMutex       sync.RWMutex
+	forbiddenArgsForCall []struct {
+		w http.ResponseWriter
+		r *http.Request
+	}
+}
+
+func (fake *FakeRejector) Unauthorized(w http.ResponseWriter, r *http.Request) {
+	fake.unauthorizedMutex.Lock()
+	fake.unauthorizedArgsForCall = append(fake.unauthorizedArgsForCall, struct {
+		w http.ResponseWriter
+		r *http.Request
+	}{w, r})
+	fake.unauthorizedMutex.Unlock()
+	if fake.UnauthorizedStub != nil {
+		fake.UnauthorizedStub(w, r)
+	}
+}
+
+func (fake *FakeRejector) UnauthorizedCallCount() int {
+	fake.unauthorizedMutex.RLock()
+	defer fake.unauthorizedMutex.RUnlock()
+	return len(fake.unauthorizedArgsForCall)
+}
+
+func (fake *FakeRejector) Forbidden(w http.ResponseWriter, r *http.Request) {
+	fake.forbiddenMutex.Lock()
+	fake.forbiddenArgsForCall = append(fake.forbiddenArgsForCall, struct {
+		w http.ResponseWriter
+		r *http.Request
+	}{w, r})
+	fake.forbiddenMutex.Unlock()
+	if fake.ForbiddenStub != nil {
+		fake.ForbiddenStub(w, r)
+	}
+}
+
+func (fake *FakeRejector) ForbiddenCallCount() int {
+	fake.forbiddenMutex.RLock()
+	defer fake.forbiddenMutex.RUnlock()
+	return len(fake.forbiddenArgsForCall)
+}
+
+var _ auth.Rejector = new(FakeRejector)
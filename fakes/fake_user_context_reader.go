@@ -0,0 +1,90 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/verifier"
+)
+
+type FakeUserContextReader struct {
+	GetTeamStub        func(r *http.Request) (string, int, bool, bool)
+	getTeamMutex       sync.RWMutex
+	getTeamArgsForCall []struct {
+		r *http.Request
+	}
+	getTeamReturns struct {
+		result1 string
+		result2 int
+		result3 bool
+		result4 bool
+	}
+
+	GetScopesStub        func(r *http.Request) ([]verifier.Scope, bool)
+	getScopesMutex       sync.RWMutex
+	getScopesArgsForCall []struct {
+		r *http.Request
+	}
+	getScopesReturns struct {
+		result1 []verifier.Scope
+		result2 bool
+	}
+}
+
+func (fake *FakeUserContextReader) GetTeam(r *http.Request) (string, int, bool, bool) {
+	fake.getTeamMutex.Lock()
+	fake.getTeamArgsForCall = append(fake.getTeamArgsForCall, struct {
+		r *http.Request
+	}{r})
+	fake.getTeamMutex.Unlock()
+	if fake.GetTeamStub != nil {
+		return fake.GetTeamStub(r)
+	}
+	return fake.getTeamReturns.result1, fake.getTeamReturns.result2, fake.getTeamReturns.result3, fake.getTeamReturns.result4
+}
+
+func (fake *FakeUserContextReader) GetTeamCallCount() int {
+	fake.getTeamMutex.RLock()
+	defer fake.getTeamMutex.RUnlock()
+	return len(fake.getTeamArgsForCall)
+}
+
+func (fake *FakeUserContextReader) GetTeamReturns(result1 string, result2 int, result3 bool, result4 bool) {
+	fake.GetTeamStub = nil
+	fake.getTeamReturns = struct {
+		result1 string
+		result2 int
+		result3 bool
+		result4 bool
+	}{result1, result2, result3, result4}
+}
+
+func (fake *FakeUserContextReader) GetScopes(r *http.Request) ([]verifier.Scope, bool) {
+	fake.getScopesMutex.Lock()
+	fake.getScopesArgsForCall = append(fake.getScopesArgsForCall, struct {
+		r *http.Request
+	}{r})
+	fake.getScopesMutex.Unlock()
+	if fake.GetScopesStub != nil {
+		return fake.GetScopesStub(r)
+	}
+	return fake.getScopesReturns.result1, fake.getScopesReturns.result2
+}
+
+func (fake *FakeUserContextReader) GetScopesCallCount() int {
+	fake.getScopesMutex.RLock()
+	defer fake.getScopesMutex.RUnlock()
+	return len(fake.getScopesArgsForCall)
+}
+
+func (fake *FakeUserContextReader) GetScopesReturns(result1 []verifier.Scope, result2 bool) {
+	fake.GetScopesStub = nil
+	fake.getScopesReturns = struct {
+		result1 []verifier.Scope
+		result2 bool
+	}{result1, result2}
+}
+
+var _ auth.UserContextReader = new(FakeUserContextReader)
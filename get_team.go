@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gorilla/context"
+
+	"github.com/concourse/atc/auth/verifier"
 )
 
 func GetTeam(r *http.Request) (string, int, bool, bool) {
@@ -17,3 +19,16 @@ func GetTeam(r *http.Request) (string, int, bool, bool) {
 
 	return teamName.(string), teamID.(int), isAdmin.(bool), true
 }
+
+// GetScopes is GetTeam's counterpart for the scopes UserContextReader.GetScopes
+// minted onto the request: WrapHandler stores them under scopesKey alongside
+// teamNameKey/teamIDKey/isAdminKey, and a handler downstream of it can call
+// this to gate on {resource, role} rather than only teamName/isAdmin.
+func GetScopes(r *http.Request) ([]verifier.Scope, bool) {
+	scopes, present := context.GetOk(r, scopesKey)
+	if !present {
+		return nil, false
+	}
+
+	return scopes.([]verifier.Scope), true
+}
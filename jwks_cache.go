@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc"
+)
+
+const jwksRefreshInterval = 10 * time.Minute
+
+// JWKSCache fetches and caches the signing keys for a set of extra JWT
+// issuers (via OIDC discovery), refreshing them periodically so a key
+// rotation on the issuer's side doesn't require restarting the ATC.
+type JWKSCache struct {
+	issuers ExtraJWTIssuers
+
+	mu      sync.Mutex
+	keySets map[string]oidc.KeySet
+	fetched map[string]time.Time
+}
+
+func NewJWKSCache(issuers ExtraJWTIssuers) *JWKSCache {
+	return &JWKSCache{
+		issuers: issuers,
+		keySets: map[string]oidc.KeySet{},
+		fetched: map[string]time.Time{},
+	}
+}
+
+// Verify checks rawToken's signature against the JWKS of whichever
+// configured issuer claims the matching 'iss', returning the issuer it
+// verified against so the caller can apply its audience/groups config.
+func (c *JWKSCache) Verify(ctx context.Context, issuerURL string, rawToken string) (*ExtraJWTIssuer, []byte, error) {
+	for i := range c.issuers {
+		issuer := c.issuers[i]
+		if issuer.IssuerURL != issuerURL {
+			continue
+		}
+
+		keySet, err := c.keySetFor(ctx, issuer.IssuerURL)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		payload, err := keySet.VerifySignature(ctx, rawToken)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &issuer, payload, nil
+	}
+
+	return nil, nil, fmt.Errorf("no extra JWT issuer configured for %q", issuerURL)
+}
+
+func (c *JWKSCache) keySetFor(ctx context.Context, issuerURL string) (oidc.KeySet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keySet, ok := c.keySets[issuerURL]
+	if ok && time.Since(c.fetched[issuerURL]) < jwksRefreshInterval {
+		return keySet, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, issuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var discovery struct {
+		JWKSURL string `json:"jwks_uri"`
+	}
+	if err := provider.Claims(&discovery); err != nil {
+		return nil, err
+	}
+	if discovery.JWKSURL == "" {
+		return nil, fmt.Errorf("issuer %q did not advertise a jwks_uri", issuerURL)
+	}
+
+	keySet = oidc.NewRemoteKeySet(ctx, discovery.JWKSURL)
+	c.keySets[issuerURL] = keySet
+	c.fetched[issuerURL] = time.Now()
+
+	return keySet, nil
+}
@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/concourse/atc/auth/verifier"
+	"github.com/dgrijalva/jwt-go"
+)
+
+//go:generate counterfeiter . UserContextReader
+
+type UserContextReader interface {
+	GetTeam(r *http.Request) (teamName string, teamID int, isAdmin bool, found bool)
+
+	// GetScopes returns the scopes granted by the ATC-Authorization token,
+	// if any were minted for it. Most tokens carry none - teamName/isAdmin
+	// from GetTeam alone is still the primary authorization mechanism - so
+	// callers that don't care about fine-grained scopes can ignore this.
+	GetScopes(r *http.Request) (scopes []verifier.Scope, found bool)
+}
+
+// JWTReader is the UserContextReader that backs production ATCs: it parses
+// the ATC-Authorization bearer token (reassembling it first if it was split
+// across ATC-Authorization_0, ATC-Authorization_1, ... by
+// WriteChunkedCookie) and pulls the team claims out of it.
+//
+// A request may also arrive with a raw `Authorization: Bearer <jwt>` signed
+// by one of the issuers configured on JWKSCache rather than the ATC's own
+// key (service accounts, CI systems, ...); the issuer named by the token's
+// 'iss' claim is tried only once the ATC key fails to validate the token,
+// preserving today's keyFunc behavior as the fast path.
+type JWTReader struct {
+	PublicKey    *rsa.PublicKey
+	ExtraIssuers ExtraJWTIssuers
+	JWKSCache    *JWKSCache
+}
+
+func (reader JWTReader) GetTeam(r *http.Request) (string, int, bool, bool) {
+	rawToken, err := reader.rawToken(r)
+	if err != nil {
+		return "", 0, false, false
+	}
+
+	if token, err := reader.parseATCToken(rawToken); err == nil && token.Valid {
+		return reader.teamFromATCClaims(token)
+	}
+
+	return reader.teamFromExtraIssuer(r.Context(), rawToken)
+}
+
+func (reader JWTReader) GetScopes(r *http.Request) ([]verifier.Scope, bool) {
+	rawToken, err := reader.rawToken(r)
+	if err != nil {
+		return nil, false
+	}
+
+	token, err := reader.parseATCToken(rawToken)
+	if err != nil || !token.Valid {
+		return nil, false
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, false
+	}
+
+	return scopesFromClaims(claims)
+}
+
+// scopesFromClaims decodes the "scopes" claim (if any) minted by
+// mintToken's scopes parameter back into []verifier.Scope. Shared between
+// GetScopes and TokenRefresher, which needs to carry a token's existing
+// scopes forward across a refresh.
+func scopesFromClaims(claims jwt.MapClaims) ([]verifier.Scope, bool) {
+	rawScopes, ok := claims["scopes"].([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	scopes := make([]verifier.Scope, 0, len(rawScopes))
+
+	for _, rawScope := range rawScopes {
+		scopeString, ok := rawScope.(string)
+		if !ok {
+			continue
+		}
+
+		sep := strings.LastIndex(scopeString, ":")
+		if sep < 0 {
+			continue
+		}
+
+		scopes = append(scopes, verifier.Scope{
+			Resource: scopeString[:sep],
+			Role:     scopeString[sep+1:],
+		})
+	}
+
+	return scopes, true
+}
+
+func (reader JWTReader) teamFromATCClaims(token *jwt.Token) (string, int, bool, bool) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", 0, false, false
+	}
+
+	teamName, ok := claims["teamName"].(string)
+	if !ok {
+		return "", 0, false, false
+	}
+
+	teamID, ok := claims["teamID"].(float64)
+	if !ok {
+		return "", 0, false, false
+	}
+
+	isAdmin, _ := claims["isAdmin"].(bool)
+
+	return teamName, int(teamID), isAdmin, true
+}
+
+func (reader JWTReader) parseATCToken(rawToken string) (*jwt.Token, error) {
+	return jwt.Parse(rawToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+		}
+
+		return reader.PublicKey, nil
+	})
+}
+
+// teamFromExtraIssuer verifies rawToken against whichever configured issuer
+// its 'iss' claim names, checks 'exp'/'nbf'/'aud', and maps its groups
+// claim to one of the issuer's allowed Concourse teams.
+func (reader JWTReader) teamFromExtraIssuer(ctx context.Context, rawToken string) (string, int, bool, bool) {
+	if reader.JWKSCache == nil {
+		return "", 0, false, false
+	}
+
+	unverified, err := unverifiedClaims(rawToken)
+	if err != nil {
+		return "", 0, false, false
+	}
+
+	issuerURL, _ := unverified["iss"].(string)
+
+	issuer, payload, err := reader.JWKSCache.Verify(ctx, issuerURL, rawToken)
+	if err != nil {
+		return "", 0, false, false
+	}
+
+	var rawClaims map[string]interface{}
+	if err := json.Unmarshal(payload, &rawClaims); err != nil {
+		return "", 0, false, false
+	}
+
+	claims := jwt.MapClaims(rawClaims)
+	if err := claims.Valid(); err != nil {
+		return "", 0, false, false
+	}
+
+	if !audienceContains(claims["aud"], issuer.Audience) {
+		return "", 0, false, false
+	}
+
+	groups, _ := claims[issuer.groupsClaim()].([]interface{})
+	for _, group := range groups {
+		groupName := fmt.Sprintf("%v", group)
+
+		for _, team := range issuer.Teams {
+			if groupName == team {
+				return team, 0, false, true
+			}
+		}
+	}
+
+	return "", 0, false, false
+}
+
+// audienceContains reports whether the 'aud' claim (either a single string
+// or, per the OIDC spec, an array of strings) contains expected.
+func audienceContains(aud interface{}, expected string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == expected
+	case []interface{}:
+		for _, entry := range aud {
+			if s, ok := entry.(string); ok && s == expected {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func unverifiedClaims(rawToken string) (jwt.MapClaims, error) {
+	parser := &jwt.Parser{}
+
+	token, _, err := parser.ParseUnverified(rawToken, jwt.MapClaims{})
+	if err != nil {
+		return nil, err
+	}
+
+	return token.Claims.(jwt.MapClaims), nil
+}
+
+func (reader JWTReader) rawToken(r *http.Request) (string, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		return strings.TrimPrefix(header, "Bearer "), nil
+	}
+
+	cookieValue, err := ReadChunkedCookie(r, CookieName)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(cookieValue, "Bearer "), nil
+}
@@ -0,0 +1,99 @@
+package keycloak
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// jwksCache fetches a realm's JWKS once and reuses it until ttl elapses,
+// avoiding a round-trip to the realm on every single request Verify
+// handles - the cost SpaceVerifier pays per call to the CF API today.
+type jwksCache struct {
+	jwksURL string
+	ttl     time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+func newJWKSCache(jwksURL string, ttl time.Duration) *jwksCache {
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+
+	return &jwksCache{
+		jwksURL: jwksURL,
+		ttl:     ttl,
+		keys:    map[string]*rsa.PublicKey{},
+	}
+}
+
+func (c *jwksCache) key(kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.fetchedAt) > c.ttl {
+		if err := c.refresh(); err != nil {
+			return nil, err
+		}
+	}
+
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("keycloak: no key found for kid %q", kid)
+	}
+
+	return key, nil
+}
+
+type jwkSet struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func (c *jwksCache) refresh() error {
+	response, err := http.Get(c.jwksURL)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	var set jwkSet
+	if err := json.NewDecoder(response.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+
+	for _, k := range set.Keys {
+		nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+
+		eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(nBytes),
+			E: int(new(big.Int).SetBytes(eBytes).Int64()),
+		}
+	}
+
+	c.keys = keys
+	c.fetchedAt = time.Now()
+
+	return nil
+}
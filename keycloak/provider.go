@@ -0,0 +1,38 @@
+package keycloak
+
+import (
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"github.com/concourse/atc/auth/verifier"
+	"github.com/pivotal-golang/lager"
+)
+
+// Provider speaks standard OAuth2 to a Keycloak realm; what makes it
+// Keycloak-specific is its Verifier, which decodes realm_access/
+// resource_access/groups claims directly off the token instead of treating
+// the realm as an opaque userinfo endpoint the way generic.Provider does.
+type Provider struct {
+	*oauth2.Config
+	verifier.Verifier
+}
+
+func (Provider) DisplayName() string {
+	return DisplayName
+}
+
+// VerifyScopes forwards to the embedded Verifier's VerifyScopes. It exists
+// as a method directly on Provider - rather than relying on Go to promote
+// it from the embedded verifier.Verifier field, which it won't, since that
+// field's static type only has a Verify method - so that callers can
+// recover scopes with a plain type assertion against provider.Provider,
+// the same way they recover Refresher.
+func (p Provider) VerifyScopes(logger lager.Logger, httpClient *http.Client) ([]verifier.Scope, error) {
+	scopeVerifier, ok := p.Verifier.(verifier.ScopeVerifier)
+	if !ok {
+		return nil, nil
+	}
+
+	return scopeVerifier.VerifyScopes(logger, httpClient)
+}
@@ -0,0 +1,108 @@
+package keycloak
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/jessevdk/go-flags"
+	"golang.org/x/oauth2"
+
+	"github.com/concourse/atc/auth/provider"
+)
+
+const ProviderName = "keycloak"
+const DisplayName = "Keycloak"
+
+func init() {
+	provider.Register(ProviderName, TeamProvider{})
+}
+
+type AuthConfig struct {
+	ClientID      string   `json:"client_id"      long:"client-id"      description:"Application client ID for enabling Keycloak OAuth."`
+	ClientSecret  string   `json:"client_secret"  long:"client-secret"  description:"Application client secret for enabling Keycloak OAuth."`
+	AuthURL       string   `json:"auth_url"       long:"auth-url"       description:"Keycloak realm's authorization endpoint."`
+	TokenURL      string   `json:"token_url"      long:"token-url"      description:"Keycloak realm's token endpoint."`
+	JWKSURL       string   `json:"jwks_url"       long:"jwks-url"       description:"Keycloak realm's certs (JWKS) endpoint."`
+	IssuerURL     string   `json:"issuer_url"     long:"issuer-url"     description:"Keycloak realm's issuer URL (the 'iss' claim on its tokens), e.g. https://keycloak.example.com/realms/myrealm."`
+	AllowedRoles  []string `json:"allowed_roles,omitempty"  long:"allowed-role"  description:"realm_access/resource_access role allowed to log in."`
+	AllowedGroups []string `json:"allowed_groups,omitempty" long:"allowed-group" description:"groups claim value allowed to log in."`
+	AllowedUsers  []string `json:"allowed_users,omitempty"  long:"allowed-user"  description:"preferred_username allowed to log in."`
+}
+
+func (config *AuthConfig) IsConfigured() bool {
+	return config.ClientID != "" || config.ClientSecret != "" || config.AuthURL != ""
+}
+
+func (config *AuthConfig) Validate() error {
+	var errs *multierror.Error
+
+	if config.ClientID == "" || config.ClientSecret == "" {
+		errs = multierror.Append(errs, errors.New("must specify --keycloak-client-id and --keycloak-client-secret to use Keycloak."))
+	}
+
+	if config.AuthURL == "" || config.TokenURL == "" || config.JWKSURL == "" || config.IssuerURL == "" {
+		errs = multierror.Append(errs, errors.New("must specify --keycloak-auth-url, --keycloak-token-url, --keycloak-jwks-url and --keycloak-issuer-url to use Keycloak."))
+	}
+
+	if len(config.AllowedRoles) == 0 && len(config.AllowedGroups) == 0 && len(config.AllowedUsers) == 0 {
+		errs = multierror.Append(errs, errors.New("must specify --keycloak-allowed-role, --keycloak-allowed-group or --keycloak-allowed-user to use Keycloak."))
+	}
+
+	return errs.ErrorOrNil()
+}
+
+type TeamProvider struct{}
+
+func (TeamProvider) AddAuthGroup(group *flags.Group) provider.AuthConfig {
+	authFlags := &AuthConfig{}
+
+	authGroup, err := group.AddGroup("Keycloak Authentication", "", authFlags)
+	if err != nil {
+		panic(err)
+	}
+
+	authGroup.Namespace = "keycloak"
+
+	return authFlags
+}
+
+func (TeamProvider) UnmarshalConfig(config *json.RawMessage) (provider.AuthConfig, error) {
+	authFlags := &AuthConfig{}
+	if config != nil {
+		if err := json.Unmarshal(*config, authFlags); err != nil {
+			return nil, err
+		}
+	}
+
+	return authFlags, nil
+}
+
+func (TeamProvider) ProviderConstructor(
+	config provider.AuthConfig,
+	redirectURL string,
+) (provider.Provider, bool) {
+	authConfig := config.(*AuthConfig)
+
+	return Provider{
+		Config: &oauth2.Config{
+			ClientID:     authConfig.ClientID,
+			ClientSecret: authConfig.ClientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  authConfig.AuthURL,
+				TokenURL: authConfig.TokenURL,
+			},
+			RedirectURL: redirectURL,
+		},
+		Verifier: NewVerifier(
+			authConfig.JWKSURL,
+			10*time.Minute,
+			authConfig.IssuerURL,
+			authConfig.ClientID,
+			authConfig.AllowedRoles,
+			authConfig.AllowedGroups,
+			authConfig.AllowedUsers,
+		),
+	}, true
+}
@@ -0,0 +1,265 @@
+package keycloak
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/concourse/atc/auth/verifier"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pivotal-golang/lager"
+)
+
+// Verifier decodes the access/ID token's realm_access.roles,
+// resource_access.<client>.roles and groups claims and matches them against
+// configured allow-lists, verifying the token's signature against the
+// realm's JWKS (cached for jwksTTL) rather than round-tripping to a CF-style
+// API per Verify call the way uaa.SpaceVerifier does.
+type Verifier struct {
+	issuerURL     string
+	clientID      string
+	allowedRoles  []string
+	allowedGroups []string
+	allowedUsers  []string
+
+	jwks *jwksCache
+}
+
+func NewVerifier(
+	realmJWKSURL string,
+	jwksTTL time.Duration,
+	issuerURL string,
+	clientID string,
+	allowedRoles []string,
+	allowedGroups []string,
+	allowedUsers []string,
+) Verifier {
+	return Verifier{
+		issuerURL:     issuerURL,
+		clientID:      clientID,
+		allowedRoles:  allowedRoles,
+		allowedGroups: allowedGroups,
+		allowedUsers:  allowedUsers,
+		jwks:          newJWKSCache(realmJWKSURL, jwksTTL),
+	}
+}
+
+type realmAccess struct {
+	Roles []string `json:"roles"`
+}
+
+type claims struct {
+	PreferredUsername string                 `json:"preferred_username"`
+	Groups            []string               `json:"groups"`
+	RealmAccess       realmAccess            `json:"realm_access"`
+	ResourceAccess    map[string]realmAccess `json:"resource_access"`
+}
+
+func (verifier Verifier) Verify(logger lager.Logger, httpClient *http.Client) (bool, error) {
+	oauth2Transport, ok := httpClient.Transport.(*oauth2.Transport)
+	if !ok {
+		return false, errors.New("httpClient transport must be of type oauth2.Transport")
+	}
+
+	token, err := oauth2Transport.Source.Token()
+	if err != nil {
+		return false, err
+	}
+
+	claims, err := verifier.verifiedClaims(token.AccessToken)
+	if err != nil {
+		logger.Error("failed-to-verify-token", err)
+		return false, err
+	}
+
+	for _, allowed := range verifier.allowedUsers {
+		if allowed == claims.PreferredUsername {
+			return true, nil
+		}
+	}
+
+	for _, group := range claims.Groups {
+		if contains(verifier.allowedGroups, group) {
+			return true, nil
+		}
+	}
+
+	for _, role := range claims.RealmAccess.Roles {
+		if contains(verifier.allowedRoles, role) {
+			return true, nil
+		}
+	}
+
+	if resource, ok := claims.ResourceAccess[verifier.clientID]; ok {
+		for _, role := range resource.Roles {
+			if contains(verifier.allowedRoles, role) {
+				return true, nil
+			}
+		}
+	}
+
+	logger.Info("not-in-allowed-roles-or-groups", lager.Data{
+		"want-roles":  verifier.allowedRoles,
+		"want-groups": verifier.allowedGroups,
+	})
+
+	return false, nil
+}
+
+// VerifyScopes grants one Scope per matched role/group rather than an
+// all-or-nothing pass. Each Scope's Role is the matched group or role name
+// itself, and its Resource is "group", "realm", or the client ID; matched
+// users grant no scope.
+func (v Verifier) VerifyScopes(logger lager.Logger, httpClient *http.Client) ([]verifier.Scope, error) {
+	oauth2Transport, ok := httpClient.Transport.(*oauth2.Transport)
+	if !ok {
+		return nil, errors.New("httpClient transport must be of type oauth2.Transport")
+	}
+
+	token, err := oauth2Transport.Source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := v.verifiedClaims(token.AccessToken)
+	if err != nil {
+		logger.Error("failed-to-verify-token", err)
+		return nil, err
+	}
+
+	var scopes []verifier.Scope
+
+	for _, group := range claims.Groups {
+		if contains(v.allowedGroups, group) {
+			scopes = append(scopes, verifier.Scope{Resource: "group", Role: group})
+		}
+	}
+
+	for _, role := range claims.RealmAccess.Roles {
+		if contains(v.allowedRoles, role) {
+			scopes = append(scopes, verifier.Scope{Resource: "realm", Role: role})
+		}
+	}
+
+	if resource, ok := claims.ResourceAccess[v.clientID]; ok {
+		for _, role := range resource.Roles {
+			if contains(v.allowedRoles, role) {
+				scopes = append(scopes, verifier.Scope{Resource: v.clientID, Role: role})
+			}
+		}
+	}
+
+	return scopes, nil
+}
+
+func (verifier Verifier) verifiedClaims(accessToken string) (*claims, error) {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("access token contains an invalid number of segments")
+	}
+
+	header, err := jwt.DecodeSegment(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var head struct {
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &head); err != nil {
+		return nil, err
+	}
+
+	if head.Alg != "RS256" {
+		return nil, fmt.Errorf("keycloak: unexpected signing algorithm %q", head.Alg)
+	}
+
+	publicKey, err := verifier.jwks.key(head.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(publicKey, parts); err != nil {
+		return nil, err
+	}
+
+	payload, err := jwt.DecodeSegment(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var standardClaims jwt.MapClaims
+	if err := json.Unmarshal(payload, &standardClaims); err != nil {
+		return nil, err
+	}
+
+	if err := standardClaims.Valid(); err != nil {
+		return nil, err
+	}
+
+	if iss, _ := standardClaims["iss"].(string); iss != verifier.issuerURL {
+		return nil, fmt.Errorf("keycloak: unexpected issuer %q", iss)
+	}
+
+	// Keycloak access tokens carry the client ID in 'aud' only when the
+	// client has "Add to access token" enabled on its audience mapper;
+	// otherwise they fall back to the default "account" audience. Accept
+	// either rather than requiring deployments to configure the mapper.
+	if !audienceContains(standardClaims["aud"], verifier.clientID) && !audienceContains(standardClaims["aud"], "account") {
+		return nil, fmt.Errorf("keycloak: unexpected audience %v", standardClaims["aud"])
+	}
+
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// audienceContains reports whether the 'aud' claim (either a single string
+// or, per the JWT spec, an array of strings) contains expected.
+func audienceContains(aud interface{}, expected string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == expected
+	case []interface{}:
+		for _, entry := range aud {
+			if s, ok := entry.(string); ok && s == expected {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func verifySignature(publicKey *rsa.PublicKey, parts []string) error {
+	signature, err := jwt.DecodeSegment(parts[2])
+	if err != nil {
+		return err
+	}
+
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(parts[0] + "." + parts[1]))
+
+	return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest.Sum(nil), signature)
+}
+
+func contains(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+
+	return false
+}
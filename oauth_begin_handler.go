@@ -2,16 +2,16 @@ package auth
 
 import (
 	"crypto/rsa"
-	"encoding/base64"
-	"encoding/json"
 	"net/http"
 	"time"
 
 	"github.com/concourse/atc/auth/provider"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/pivotal-golang/lager"
 )
 
 const OAuthStateCookie = "_concourse_oauth_state"
+const OAuthStateAge = 10 * time.Minute
 
 type OAuthState struct {
 	Redirect string `json:"redirect"`
@@ -36,11 +36,15 @@ func NewOAuthBeginHandler(
 }
 
 func (handler *OAuthBeginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := handler.logger.Session("serve", lager.Data{
+		"request-id": GetRequestID(r),
+	})
+
 	providerName := r.FormValue(":provider")
 
 	provider, found := handler.providers[providerName]
 	if !found {
-		handler.logger.Info("unknown-provider", lager.Data{
+		logger.Info("unknown-provider", lager.Data{
 			"provider": providerName,
 		})
 
@@ -48,25 +52,33 @@ func (handler *OAuthBeginHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	oauthState, err := json.Marshal(OAuthState{
+	exp := time.Now().Add(OAuthStateAge)
+
+	signedState, err := signOAuthState(handler.privateKey, OAuthState{
 		Redirect: r.FormValue("redirect"),
-	})
+	}, exp)
 	if err != nil {
-		handler.logger.Error("failed-to-marshal-state", err)
+		logger.Error("failed-to-sign-state", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	encodedState := base64.RawURLEncoding.EncodeToString(oauthState)
-
-	authCodeURL := provider.AuthCodeURL(encodedState)
+	authCodeURL := provider.AuthCodeURL(signedState)
 
-	http.SetCookie(w, &http.Cookie{
-		Name:    OAuthStateCookie,
-		Value:   encodedState,
-		Path:    "/",
-		Expires: time.Now().Add(CookieAge),
-	})
+	WriteChunkedCookie(w, OAuthStateCookie, signedState, "/", exp)
 
 	http.Redirect(w, r, authCodeURL, http.StatusTemporaryRedirect)
 }
+
+// signOAuthState wraps state in a JWT signed with the ATC's own key, the
+// same way mintToken signs the session token, so OAuthCallbackHandler can
+// tell a state round-tripped through a real provider redirect from one an
+// attacker forged or replayed from an earlier, expired flow.
+func signOAuthState(privateKey *rsa.PrivateKey, state OAuthState, exp time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"redirect": state.Redirect,
+		"exp":      exp.Unix(),
+	}
+
+	return jwt.NewWithClaims(SigningMethod, claims).SignedString(privateKey)
+}
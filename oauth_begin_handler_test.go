@@ -0,0 +1,97 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/dgrijalva/jwt-go"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/lager/lagertest"
+
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/provider"
+	"github.com/concourse/atc/auth/provider/fakes"
+)
+
+var _ = Describe("OAuthBeginHandler", func() {
+	var (
+		fakeProvider *fakes.FakeProvider
+		signingKey   *rsa.PrivateKey
+
+		server   *httptest.Server
+		client   *http.Client
+		response *http.Response
+	)
+
+	BeforeEach(func() {
+		fakeProvider = new(fakes.FakeProvider)
+		fakeProvider.AuthCodeURLReturns("http://provider.example.com/auth")
+
+		var err error
+		signingKey, err = rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).ToNot(HaveOccurred())
+
+		handler := auth.NewOAuthBeginHandler(
+			lagertest.NewTestLogger("test"),
+			provider.Providers{"some-provider": fakeProvider},
+			signingKey,
+		)
+
+		mux := http.NewServeMux()
+		mux.Handle("/auth/", handler)
+
+		server = httptest.NewServer(mux)
+
+		client = &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		request, err := http.NewRequest("GET", server.URL+"/auth/:provider?:provider=some-provider&redirect=/pipelines/foo", nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		response, err = client.Do(request)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("redirects to the provider's auth code URL", func() {
+		Expect(response.StatusCode).To(Equal(http.StatusTemporaryRedirect))
+		Expect(response.Header.Get("Location")).To(Equal("http://provider.example.com/auth"))
+	})
+
+	It("sets a state cookie signed with the ATC's key", func() {
+		cookies := response.Cookies()
+		Expect(cookies).To(HaveLen(1))
+		Expect(cookies[0].Name).To(Equal(auth.OAuthStateCookie))
+
+		signedState := cookies[0].Value
+
+		token, err := jwt.Parse(signedState, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+
+			return signingKey.Public(), nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token.Valid).To(BeTrue())
+
+		claims := token.Claims.(jwt.MapClaims)
+		Expect(claims["redirect"]).To(Equal("/pipelines/foo"))
+	})
+
+	It("passes the signed state along as the OAuth state param", func() {
+		cookies := response.Cookies()
+		Expect(cookies).To(HaveLen(1))
+
+		state, _ := fakeProvider.AuthCodeURLArgsForCall(0)
+		Expect(state).To(Equal(cookies[0].Value))
+	})
+})
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/concourse/atc/auth/provider"
+	"github.com/concourse/atc/auth/verifier"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pivotal-golang/lager"
+)
+
+const CookieName = "ATC-Authorization"
+const CookieAge = 24 * time.Hour
+
+type OAuthCallbackHandler struct {
+	logger           lager.Logger
+	providers        provider.Providers
+	privateKey       *rsa.PrivateKey
+	whitelistDomains []string
+}
+
+func NewOAuthCallbackHandler(
+	logger lager.Logger,
+	providers provider.Providers,
+	privateKey *rsa.PrivateKey,
+	whitelistDomains ...string,
+) http.Handler {
+	return &OAuthCallbackHandler{
+		logger:           logger,
+		providers:        providers,
+		privateKey:       privateKey,
+		whitelistDomains: whitelistDomains,
+	}
+}
+
+func (handler *OAuthCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := GetRequestID(r)
+
+	logger := handler.logger.Session("serve", lager.Data{
+		"request-id": requestID,
+	})
+
+	providerName := r.FormValue(":provider")
+
+	p, found := handler.providers[providerName]
+	if !found {
+		logger.Info("unknown-provider", lager.Data{
+			"provider": providerName,
+		})
+
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	stateParam := r.FormValue("state")
+	if stateParam == "" {
+		logger.Info("missing-state")
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	stateCookieValue, err := ReadChunkedCookie(r, OAuthStateCookie)
+	if err != nil {
+		logger.Info("missing-state-cookie")
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stateCookieValue), []byte(stateParam)) != 1 {
+		logger.Info("state-mismatch")
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	oauthState, err := handler.verifyOAuthState(stateParam)
+	if err != nil {
+		logger.Info("failed-to-verify-state", lager.Data{"error": err.Error()})
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+
+	token, err := p.Exchange(ctx, r.FormValue("code"))
+	if err != nil {
+		logger.Error("failed-to-exchange-token", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	httpClient := WrapClientWithRequestID(p.Client(ctx, token), requestID)
+
+	verified, err := p.Verify(logger.Session("verify"), httpClient)
+	if err != nil {
+		logger.Error("failed-to-verify-token", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if !verified {
+		logger.Info("verification-failed")
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var scopes []verifier.Scope
+
+	if scopeVerifier, ok := p.(verifier.ScopeVerifier); ok {
+		scopes, err = scopeVerifier.VerifyScopes(logger.Session("verify-scopes"), httpClient)
+		if err != nil {
+			logger.Error("failed-to-verify-scopes", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	exp := time.Now().Add(CookieAge)
+
+	signedToken, err := mintToken(handler.privateKey, providerName, token, exp, scopes)
+	if err != nil {
+		logger.Error("failed-to-sign-token", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	cookieValue := "Bearer " + signedToken
+
+	WriteChunkedCookie(w, CookieName, cookieValue, "/", exp)
+
+	if IsValidRedirect(oauthState.Redirect, r.Host, handler.whitelistDomains) {
+		http.Redirect(w, r, oauthState.Redirect, http.StatusTemporaryRedirect)
+		return
+	}
+
+	fmt.Fprintln(w, cookieValue)
+}
+
+// verifyOAuthState checks the signature signOAuthState produced (rejecting
+// anything not signed by this ATC's key, or expired per its own exp claim)
+// and recovers the OAuthState it carries. The state-cookie comparison above
+// already guards against CSRF; this guards against a forged or replayed
+// state value being accepted in the first place.
+func (handler *OAuthCallbackHandler) verifyOAuthState(signedState string) (OAuthState, error) {
+	token, err := jwt.Parse(signedState, keyFunc(handler.privateKey))
+	if err != nil {
+		return OAuthState{}, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return OAuthState{}, fmt.Errorf("invalid oauth state token")
+	}
+
+	redirect, _ := claims["redirect"].(string)
+
+	return OAuthState{Redirect: redirect}, nil
+}
+
+// mintToken signs the ATC-Authorization JWT. When the exchanged token came
+// with a refresh token (as OIDC and other refreshing providers do), it's
+// carried along as a claim, alongside the provider it came from, so
+// TokenRefresher can silently refresh the session once the access/ID token
+// is close to CookieAge instead of forcing the user back through the
+// browser OAuth dance.
+func mintToken(privateKey *rsa.PrivateKey, providerName string, token *oauth2.Token, exp time.Time, scopes []verifier.Scope) (string, error) {
+	claims := jwt.MapClaims{
+		"exp": exp.Unix(),
+	}
+
+	if token.RefreshToken != "" {
+		claims["refresh_token"] = token.RefreshToken
+		claims["provider"] = providerName
+	}
+
+	if idToken, ok := token.Extra("id_token").(string); ok {
+		claims["id_token"] = idToken
+	}
+
+	if len(scopes) > 0 {
+		scopeStrings := make([]string, len(scopes))
+		for i, scope := range scopes {
+			scopeStrings[i] = scope.String()
+		}
+
+		claims["scopes"] = scopeStrings
+	}
+
+	jwtToken := jwt.NewWithClaims(SigningMethod, claims)
+
+	return jwtToken.SignedString(privateKey)
+}
@@ -3,8 +3,6 @@ package auth_test
 import (
 	"crypto/rand"
 	"crypto/rsa"
-	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -77,6 +75,18 @@ var _ = Describe("OAuthCallbackHandler", func() {
 		return signingKey.Public(), nil
 	}
 
+	signState := func(redirect string) string {
+		claims := jwt.MapClaims{
+			"redirect": redirect,
+			"exp":      time.Now().Add(time.Hour).Unix(),
+		}
+
+		signedState, err := jwt.NewWithClaims(auth.SigningMethod, claims).SignedString(signingKey)
+		Expect(err).ToNot(HaveOccurred())
+
+		return signedState
+	}
+
 	Describe("GET /auth/:provider/callback", func() {
 		var redirectTarget *ghttp.Server
 		var request *http.Request
@@ -106,21 +116,18 @@ var _ = Describe("OAuthCallbackHandler", func() {
 
 			Context("when the request's state is valid", func() {
 				BeforeEach(func() {
-					state, err := json.Marshal(auth.OAuthState{})
-					Expect(err).ToNot(HaveOccurred())
-
-					encodedState := base64.RawURLEncoding.EncodeToString(state)
+					signedState := signState("")
 
 					request.AddCookie(&http.Cookie{
 						Name:    auth.OAuthStateCookie,
-						Value:   encodedState,
+						Value:   signedState,
 						Path:    "/",
 						Expires: time.Now().Add(time.Hour),
 					})
 
 					request.URL.RawQuery = url.Values{
 						"code":  {"some-code"},
-						"state": {encodedState},
+						"state": {signedState},
 					}.Encode()
 				})
 
@@ -224,23 +231,18 @@ var _ = Describe("OAuthCallbackHandler", func() {
 
 			Context("when a redirect URI is in the state", func() {
 				BeforeEach(func() {
-					state, err := json.Marshal(auth.OAuthState{
-						Redirect: "/",
-					})
-					Expect(err).ToNot(HaveOccurred())
-
-					encodedState := base64.RawURLEncoding.EncodeToString(state)
+					signedState := signState("/")
 
 					request.AddCookie(&http.Cookie{
 						Name:    auth.OAuthStateCookie,
-						Value:   encodedState,
+						Value:   signedState,
 						Path:    "/",
 						Expires: time.Now().Add(time.Hour),
 					})
 
 					request.URL.RawQuery = url.Values{
 						"code":  {"some-code"},
-						"state": {encodedState},
+						"state": {signedState},
 					}.Encode()
 				})
 
@@ -346,14 +348,11 @@ var _ = Describe("OAuthCallbackHandler", func() {
 
 			Context("when the request's state is not set as a cookie", func() {
 				BeforeEach(func() {
-					state, err := json.Marshal(auth.OAuthState{})
-					Expect(err).ToNot(HaveOccurred())
-
-					encodedState := base64.RawURLEncoding.EncodeToString(state)
+					signedState := signState("")
 
 					request.URL.RawQuery = url.Values{
 						"code":  {"some-code"},
-						"state": {encodedState},
+						"state": {signedState},
 					}.Encode()
 				})
 
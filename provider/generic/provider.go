@@ -0,0 +1,148 @@
+package generic
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/jessevdk/go-flags"
+	"golang.org/x/oauth2"
+
+	"github.com/concourse/atc/auth/provider"
+	"github.com/concourse/atc/auth/verifier"
+)
+
+const ProviderName = "generic-oauth"
+const DisplayName = "Generic OAuth2"
+
+// Provider speaks to any OAuth2 endpoint: GitLab, Bitbucket, Keycloak,
+// Okta, or a bespoke in-house identity server. Unlike github/uaa/cf it
+// carries no assumptions about the shape of team/organization membership;
+// that's entirely driven by Verifier, built in ProviderConstructor from the
+// configured userinfo URL and allow-lists.
+type Provider struct {
+	*oauth2.Config
+	verifier.Verifier
+
+	CACert string
+}
+
+func (Provider) DisplayName() string {
+	return DisplayName
+}
+
+// PreTokenClient mirrors uaa.UAAProvider.PreTokenClient: when the operator
+// has pointed this provider at a self-hosted IdP with a private CA, the
+// OAuth2 token exchange and userinfo calls need to trust it.
+func (p Provider) PreTokenClient() (*http.Client, error) {
+	if p.CACert == "" {
+		return http.DefaultClient, nil
+	}
+
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM([]byte(p.CACert)) {
+		return nil, errors.New("failed to use generic OAuth2 CA certificate")
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: caCertPool},
+		},
+	}, nil
+}
+
+type AuthConfig struct {
+	AuthURL      string   `json:"auth_url"      long:"auth-url"      description:"Generic OAuth2 provider AuthURL endpoint."`
+	TokenURL     string   `json:"token_url"     long:"token-url"     description:"Generic OAuth2 provider TokenURL endpoint."`
+	UserInfoURL  string   `json:"userinfo_url"  long:"userinfo-url"  description:"Generic OAuth2 provider userinfo endpoint."`
+	ClientID     string   `json:"client_id"     long:"client-id"     description:"Application client ID for enabling generic OAuth2."`
+	ClientSecret string   `json:"client_secret" long:"client-secret" description:"Application client secret for enabling generic OAuth2."`
+	Scopes       []string `json:"scopes,omitempty"        long:"scopes"       description:"Scopes to request when authenticating."`
+	UsersKey     string   `json:"users_key,omitempty"     long:"users-key"    description:"Userinfo claim (JSON pointer, e.g. 'email' or 'resource_access/concourse/username') to match --generic-oauth-user against. Defaults to 'email'."`
+	GroupsKey    string   `json:"groups_key,omitempty"    long:"groups-key"   description:"Userinfo claim (JSON pointer, e.g. 'groups' or 'realm_access/roles') to match --generic-oauth-group against. Defaults to 'groups'."`
+	Users        []string `json:"users,omitempty"         long:"user"         description:"User allowed to log in, matched against --generic-oauth-users-key."`
+	Groups       []string `json:"groups,omitempty"        long:"group"        description:"Group allowed to log in, matched against --generic-oauth-groups-key."`
+	EmailDomains []string `json:"email_domains,omitempty" long:"email-domain" description:"Email domain allowed to log in, e.g. 'example.com'."`
+	CACert       string   `json:"ca_cert,omitempty"       long:"ca-cert"      description:"PEM-encoded CA certificate for the generic OAuth2 provider."`
+}
+
+func (config *AuthConfig) IsConfigured() bool {
+	return config.ClientID != "" || config.ClientSecret != "" || config.AuthURL != ""
+}
+
+func (config *AuthConfig) Validate() error {
+	var errs *multierror.Error
+
+	if config.ClientID == "" || config.ClientSecret == "" {
+		errs = multierror.Append(errs, errors.New("must specify --generic-oauth-client-id and --generic-oauth-client-secret to use generic OAuth2."))
+	}
+
+	if config.AuthURL == "" || config.TokenURL == "" || config.UserInfoURL == "" {
+		errs = multierror.Append(errs, errors.New("must specify --generic-oauth-auth-url, --generic-oauth-token-url and --generic-oauth-userinfo-url to use generic OAuth2."))
+	}
+
+	if len(config.Users) == 0 && len(config.Groups) == 0 && len(config.EmailDomains) == 0 {
+		errs = multierror.Append(errs, errors.New("must specify --generic-oauth-user, --generic-oauth-group or --generic-oauth-email-domain to use generic OAuth2."))
+	}
+
+	return errs.ErrorOrNil()
+}
+
+type TeamProvider struct{}
+
+func init() {
+	provider.Register(ProviderName, TeamProvider{})
+}
+
+func (TeamProvider) AddAuthGroup(group *flags.Group) provider.AuthConfig {
+	flags := &AuthConfig{}
+
+	authGroup, err := group.AddGroup("Generic OAuth2 Authentication", "", flags)
+	if err != nil {
+		panic(err)
+	}
+
+	authGroup.Namespace = "generic-oauth"
+
+	return flags
+}
+
+func (TeamProvider) UnmarshalConfig(config *json.RawMessage) (provider.AuthConfig, error) {
+	flags := &AuthConfig{}
+	if config != nil {
+		if err := json.Unmarshal(*config, flags); err != nil {
+			return nil, err
+		}
+	}
+
+	return flags, nil
+}
+
+func (TeamProvider) ProviderConstructor(
+	config provider.AuthConfig,
+	redirectURL string,
+) (provider.Provider, bool) {
+	authConfig := config.(*AuthConfig)
+
+	return Provider{
+		Config: &oauth2.Config{
+			ClientID:     authConfig.ClientID,
+			ClientSecret: authConfig.ClientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  authConfig.AuthURL,
+				TokenURL: authConfig.TokenURL,
+			},
+			Scopes:      authConfig.Scopes,
+			RedirectURL: redirectURL,
+		},
+		Verifier: verifier.NewBasket(
+			verifier.NewUserVerifier(authConfig.UserInfoURL, authConfig.UsersKey, authConfig.Users),
+			verifier.NewGroupVerifier(authConfig.UserInfoURL, authConfig.GroupsKey, authConfig.Groups),
+			verifier.NewEmailDomainVerifier(authConfig.UserInfoURL, authConfig.EmailDomains),
+		),
+		CACert: authConfig.CACert,
+	}, true
+}
@@ -0,0 +1,29 @@
+package oidc
+
+import (
+	"errors"
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+var ErrNoToken = errors.New("oidc: no oauth2 token on http client")
+var ErrNoIDToken = errors.New("oidc: token response did not contain an id_token")
+var ErrTokenNotYetValid = errors.New("oidc: id_token is not valid yet (nbf)")
+
+// tokenFromClient recovers the oauth2.Token used to build httpClient, which
+// is how Verify gets at the id_token, which the *http.Client built by the
+// embedded oauth2.Config's Client does not otherwise expose.
+func tokenFromClient(httpClient *http.Client) (*oauth2.Token, bool) {
+	transport, ok := httpClient.Transport.(*oauth2.Transport)
+	if !ok {
+		return nil, false
+	}
+
+	token, err := transport.Source.Token()
+	if err != nil {
+		return nil, false
+	}
+
+	return token, true
+}
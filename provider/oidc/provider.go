@@ -0,0 +1,135 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/coreos/go-oidc"
+	"github.com/pivotal-golang/lager"
+	"golang.org/x/oauth2"
+
+	"github.com/concourse/atc/auth/provider"
+)
+
+const ProviderName = "oidc"
+const DisplayName = "OpenID Connect"
+
+// Provider implements provider.Provider (and provider.Refresher) on top of
+// any OpenID Connect issuer, discovered via its
+// /.well-known/openid-configuration document. Identity is taken from the
+// verified id_token rather than from an opaque access token, so Verify
+// never needs to round-trip to the issuer.
+type Provider struct {
+	*oauth2.Config
+
+	issuer        *oidc.Provider
+	verifier      *oidc.IDTokenVerifier
+	allowedUsers  []string
+	allowedGroups []string
+}
+
+func NewProvider(
+	ctx context.Context,
+	issuerURL string,
+	clientID string,
+	clientSecret string,
+	redirectURL string,
+	allowedUsers []string,
+	allowedGroups []string,
+) (Provider, error) {
+	issuer, err := oidc.NewProvider(ctx, issuerURL)
+	if err != nil {
+		return Provider{}, err
+	}
+
+	return Provider{
+		Config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Endpoint:     issuer.Endpoint(),
+			RedirectURL:  redirectURL,
+			Scopes:       []string{oidc.ScopeOpenID, "email", "profile", oidc.ScopeOfflineAccess},
+		},
+		issuer:        issuer,
+		verifier:      issuer.Verifier(&oidc.Config{ClientID: clientID}),
+		allowedUsers:  allowedUsers,
+		allowedGroups: allowedGroups,
+	}, nil
+}
+
+func (Provider) DisplayName() string {
+	return DisplayName
+}
+
+// Verify pulls the id_token out of the token exchanged by the callback
+// handler, checks its signature against the issuer's JWKS (cached and
+// refreshed by the go-oidc library), and matches the subject/email claim
+// against the configured allow-list.
+func (p Provider) Verify(logger lager.Logger, httpClient *http.Client) (bool, error) {
+	token, ok := tokenFromClient(httpClient)
+	if !ok {
+		return false, ErrNoToken
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		logger.Info("no-id-token")
+		return false, ErrNoIDToken
+	}
+
+	idToken, err := p.verifier.Verify(oidc.ClientContext(context.Background(), httpClient), rawIDToken)
+	if err != nil {
+		logger.Error("failed-to-verify-id-token", err)
+		return false, err
+	}
+
+	// go-oidc checks iss/aud/exp (and signature) but, as of this version,
+	// doesn't enforce nbf, so do it ourselves.
+	var timing struct {
+		NotBefore int64 `json:"nbf"`
+	}
+	if err := idToken.Claims(&timing); err != nil {
+		return false, err
+	}
+	if timing.NotBefore != 0 && time.Unix(timing.NotBefore, 0).After(time.Now()) {
+		logger.Info("token-not-yet-valid")
+		return false, ErrTokenNotYetValid
+	}
+
+	var claims struct {
+		Email  string   `json:"email"`
+		Groups []string `json:"groups"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return false, err
+	}
+
+	for _, allowed := range p.allowedUsers {
+		if allowed == idToken.Subject || allowed == claims.Email {
+			return true, nil
+		}
+	}
+
+	for _, group := range claims.Groups {
+		for _, allowed := range p.allowedGroups {
+			if group == allowed {
+				return true, nil
+			}
+		}
+	}
+
+	logger.Info("not-validated-user", lager.Data{
+		"have": claims.Email,
+		"want": p.allowedUsers,
+	})
+
+	return false, nil
+}
+
+// Refresh exchanges the refresh token embedded in token for a fresh
+// access/ID token pair, so a long-lived ATC session can keep renewing
+// itself instead of expiring after CookieAge.
+func (p Provider) Refresh(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+	return p.Config.TokenSource(ctx, token).Token()
+}
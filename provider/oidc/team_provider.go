@@ -0,0 +1,94 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/jessevdk/go-flags"
+
+	"github.com/concourse/atc/auth/provider"
+)
+
+func init() {
+	provider.Register(ProviderName, TeamProvider{})
+}
+
+type AuthConfig struct {
+	IssuerURL    string   `json:"issuer_url"    long:"issuer-url"    description:"OIDC issuer URL, e.g. https://accounts.google.com. Discovery is used to locate the token, userinfo and JWKS endpoints."`
+	ClientID     string   `json:"client_id"     long:"client-id"     description:"Application client ID for enabling OIDC."`
+	ClientSecret string   `json:"client_secret" long:"client-secret" description:"Application client secret for enabling OIDC."`
+	Users        []string `json:"users,omitempty"  long:"user"  description:"'sub' or 'email' claim of a user allowed to log in."`
+	Groups       []string `json:"groups,omitempty" long:"group" description:"'groups' claim value allowed to log in."`
+}
+
+func (config *AuthConfig) IsConfigured() bool {
+	return config.ClientID != "" || config.ClientSecret != "" || config.IssuerURL != ""
+}
+
+func (config *AuthConfig) Validate() error {
+	var errs *multierror.Error
+
+	if config.ClientID == "" || config.ClientSecret == "" {
+		errs = multierror.Append(errs, errors.New("must specify --oidc-client-id and --oidc-client-secret to use OIDC."))
+	}
+
+	if config.IssuerURL == "" {
+		errs = multierror.Append(errs, errors.New("must specify --oidc-issuer-url to use OIDC."))
+	}
+
+	if len(config.Users) == 0 && len(config.Groups) == 0 {
+		errs = multierror.Append(errs, errors.New("must specify --oidc-user or --oidc-group to use OIDC."))
+	}
+
+	return errs.ErrorOrNil()
+}
+
+type TeamProvider struct{}
+
+func (TeamProvider) AddAuthGroup(group *flags.Group) provider.AuthConfig {
+	authFlags := &AuthConfig{}
+
+	authGroup, err := group.AddGroup("OpenID Connect Authentication", "", authFlags)
+	if err != nil {
+		panic(err)
+	}
+
+	authGroup.Namespace = "oidc"
+
+	return authFlags
+}
+
+func (TeamProvider) UnmarshalConfig(config *json.RawMessage) (provider.AuthConfig, error) {
+	authFlags := &AuthConfig{}
+	if config != nil {
+		if err := json.Unmarshal(*config, authFlags); err != nil {
+			return nil, err
+		}
+	}
+
+	return authFlags, nil
+}
+
+func (TeamProvider) ProviderConstructor(
+	config provider.AuthConfig,
+	redirectURL string,
+) (provider.Provider, bool) {
+	authConfig := config.(*AuthConfig)
+
+	p, err := NewProvider(
+		context.Background(),
+		authConfig.IssuerURL,
+		authConfig.ClientID,
+		authConfig.ClientSecret,
+		redirectURL,
+		authConfig.Users,
+		authConfig.Groups,
+	)
+	if err != nil {
+		return nil, false
+	}
+
+	return p, true
+}
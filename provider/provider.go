@@ -31,3 +31,13 @@ type OAuthClient interface {
 type Verifier interface {
 	Verify(lager.Logger, *http.Client) (bool, error)
 }
+
+//go:generate counterfeiter . Refresher
+
+// Refresher is implemented by providers that can exchange a refresh token
+// for a new access/ID token without sending the user back through the
+// browser OAuth dance. Providers that don't support refreshing (e.g. github)
+// simply don't implement it; callers should type-assert for it.
+type Refresher interface {
+	Refresh(context.Context, *oauth2.Token) (*oauth2.Token, error)
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"encoding/json"
+
+	"github.com/jessevdk/go-flags"
+)
+
+// AuthConfig is the per-team configuration parsed from either command-line
+// flags (AddAuthGroup) or a team's JSON auth config (UnmarshalConfig) for a
+// single registered provider.
+type AuthConfig interface {
+	IsConfigured() bool
+	Validate() error
+}
+
+// TeamProvider is implemented by OAuth2-based providers (github, uaa, cf,
+// generic, oidc, ...): ProviderConstructor builds a Provider that the OAuth
+// begin/callback handlers can drive directly.
+type TeamProvider interface {
+	AddAuthGroup(group *flags.Group) AuthConfig
+	UnmarshalConfig(config *json.RawMessage) (AuthConfig, error)
+	ProviderConstructor(config AuthConfig, redirectURL string) (Provider, bool)
+}
+
+var providers = map[string]TeamProvider{}
+
+// Register makes an OAuth2 TeamProvider available by name (e.g. "github",
+// "uaa") for teams to configure.
+func Register(providerName string, teamProvider TeamProvider) {
+	providers[providerName] = teamProvider
+}
+
+func GetTeamProvider(providerName string) (TeamProvider, bool) {
+	teamProvider, found := providers[providerName]
+	return teamProvider, found
+}
+
+var nonOAuthProviders = map[string]interface{}{}
+
+// RegisterNonOAuth makes a non-OAuth2 provider (e.g. SAML, which has no
+// authorization code to exchange and is driven by its own Begin/ACS
+// handlers rather than the shared OAuth begin/callback handlers) available
+// by name. teamProvider is typed as interface{} because each non-OAuth
+// mechanism has its own shape of team provider - SAML's, for instance,
+// returns a saml.Provider rather than a provider.Provider - so there's
+// nothing more specific to require here without coupling this package to
+// every non-OAuth provider's package.
+func RegisterNonOAuth(providerName string, teamProvider interface{}) {
+	nonOAuthProviders[providerName] = teamProvider
+}
+
+func GetNonOAuthTeamProvider(providerName string) (interface{}, bool) {
+	teamProvider, found := nonOAuthProviders[providerName]
+	return teamProvider, found
+}
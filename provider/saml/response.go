@@ -0,0 +1,166 @@
+package saml
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+var ErrConditionsNotMet = errors.New("saml: assertion conditions (NotBefore/NotOnOrAfter/Audience) not satisfied")
+var ErrDestinationMismatch = errors.New("saml: response Destination does not match the ACS URL")
+var ErrNoSignedAssertion = errors.New("saml: response does not contain an Assertion whose signature verifies against the IdP certificate")
+
+// Assertion is the verified, parsed result of an ACS callback: the
+// subject's NameID plus whatever attributes (e.g. "groups", "email") the
+// IdP included.
+type Assertion struct {
+	NameID     string
+	Attributes map[string][]string
+}
+
+// ParseAndVerifyResponse decodes a base64-encoded SAMLResponse, checks its
+// Destination, and returns the subject/attributes of whichever Assertion
+// inside it verifies against the IdP certificate.
+//
+// Real IdPs sign the Assertion itself (an enveloped <ds:Signature> as one
+// of its own children, referencing the Assertion's own ID), not the whole
+// Response, and that signature covers the exclusive XML canonicalization
+// of the element - not its raw bytes - so this delegates the actual
+// cryptographic check to goxmldsig rather than hashing bytes by hand.
+//
+// To resist signature wrapping (an attacker stapling a forged Assertion
+// into the response alongside a validly-signed one, hoping a parser reads
+// NameID off whichever Assertion it finds first), every <Assertion>
+// element in the document is handed to the validator in turn, and
+// NameID/Attributes are only ever read from the one the validator itself
+// returns - never from one merely found by tag name or ID - so a forged
+// sibling without its own valid signature can't substitute.
+func ParseAndVerifyResponse(rawResponse string, idpCertPEM string, audience string, acsURL string) (*Assertion, error) {
+	decoded, err := base64.StdEncoding.DecodeString(rawResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(decoded); err != nil {
+		return nil, err
+	}
+
+	root := doc.Root()
+	if root == nil {
+		return nil, errors.New("saml: response is not valid XML")
+	}
+
+	if destination := root.SelectAttrValue("Destination", ""); destination != "" && destination != acsURL {
+		return nil, ErrDestinationMismatch
+	}
+
+	cert, err := parseCertificate(idpCertPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	validationContext := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{
+		Roots: []*x509.Certificate{cert},
+	})
+
+	verifiedAssertion, err := firstVerifiedAssertion(root, validationContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return assertionFromElement(verifiedAssertion, audience)
+}
+
+func parseCertificate(certPEM string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return nil, errors.New("saml: invalid IdP certificate PEM")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// firstVerifiedAssertion validates every <Assertion> element in the
+// response independently and returns the signature-stripped copy of the
+// first one whose signature actually verifies against validationContext -
+// not the first one it merely finds by tag name.
+func firstVerifiedAssertion(root *etree.Element, validationContext *dsig.ValidationContext) (*etree.Element, error) {
+	for _, candidate := range root.FindElements("//Assertion") {
+		verified, err := validationContext.Validate(candidate)
+		if err == nil {
+			return verified, nil
+		}
+	}
+
+	return nil, ErrNoSignedAssertion
+}
+
+func assertionFromElement(el *etree.Element, audience string) (*Assertion, error) {
+	if conditionsEl := el.FindElement("Conditions"); conditionsEl != nil {
+		if err := checkConditions(conditionsEl, audience); err != nil {
+			return nil, err
+		}
+	}
+
+	nameID := ""
+	if nameIDEl := el.FindElement("Subject/NameID"); nameIDEl != nil {
+		nameID = nameIDEl.Text()
+	}
+
+	attributes := map[string][]string{}
+	for _, attrEl := range el.FindElements("AttributeStatement/Attribute") {
+		name := attrEl.SelectAttrValue("Name", "")
+
+		var values []string
+		for _, valueEl := range attrEl.FindElements("AttributeValue") {
+			values = append(values, valueEl.Text())
+		}
+
+		attributes[name] = values
+	}
+
+	return &Assertion{
+		NameID:     nameID,
+		Attributes: attributes,
+	}, nil
+}
+
+func checkConditions(conditionsEl *etree.Element, audience string) error {
+	now := time.Now().UTC()
+
+	if raw := conditionsEl.SelectAttrValue("NotBefore", ""); raw != "" {
+		notBefore, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return err
+		}
+
+		if now.Before(notBefore) {
+			return ErrConditionsNotMet
+		}
+	}
+
+	if raw := conditionsEl.SelectAttrValue("NotOnOrAfter", ""); raw != "" {
+		notOnOrAfter, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return err
+		}
+
+		if !now.Before(notOnOrAfter) {
+			return ErrConditionsNotMet
+		}
+	}
+
+	if audienceEl := conditionsEl.FindElement("AudienceRestriction/Audience"); audienceEl != nil {
+		if audienceEl.Text() != audience {
+			return ErrConditionsNotMet
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package saml_test
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/auth/provider/saml"
+)
+
+const acsURL = "https://atc.example.com/auth/saml/callback"
+const audience = "https://atc.example.com"
+
+var _ = Describe("ParseAndVerifyResponse", func() {
+	var (
+		keyStore dsig.X509KeyStore
+		certPEM  string
+	)
+
+	BeforeEach(func() {
+		keyStore = dsig.RandomKeyStoreForTest()
+
+		_, certDER, err := keyStore.GetKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = x509.ParseCertificate(certDER)
+		Expect(err).NotTo(HaveOccurred())
+
+		certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+	})
+
+	buildAssertion := func(id string, nameID string) *etree.Element {
+		assertion := etree.NewElement("Assertion")
+		assertion.CreateAttr("ID", id)
+
+		conditions := assertion.CreateElement("Conditions")
+		conditions.CreateAttr("NotBefore", time.Now().Add(-time.Minute).UTC().Format(time.RFC3339))
+		conditions.CreateAttr("NotOnOrAfter", time.Now().Add(time.Minute).UTC().Format(time.RFC3339))
+		conditions.CreateElement("AudienceRestriction").CreateElement("Audience").SetText(audience)
+
+		assertion.CreateElement("Subject").CreateElement("NameID").SetText(nameID)
+
+		return assertion
+	}
+
+	signAssertion := func(ks dsig.X509KeyStore, assertion *etree.Element) *etree.Element {
+		signed, err := dsig.NewDefaultSigningContext(ks).SignEnveloped(assertion)
+		Expect(err).NotTo(HaveOccurred())
+		return signed
+	}
+
+	encodedResponse := func(assertions ...*etree.Element) string {
+		doc := etree.NewDocument()
+		response := doc.CreateElement("Response")
+		response.CreateAttr("Destination", acsURL)
+		for _, a := range assertions {
+			response.AddChild(a)
+		}
+
+		raw, err := doc.WriteToBytes()
+		Expect(err).NotTo(HaveOccurred())
+
+		return base64.StdEncoding.EncodeToString(raw)
+	}
+
+	It("verifies a validly-signed assertion and returns its NameID", func() {
+		signed := signAssertion(keyStore, buildAssertion("_original-assertion", "alice"))
+
+		assertion, err := saml.ParseAndVerifyResponse(encodedResponse(signed), certPEM, audience, acsURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(assertion.NameID).To(Equal("alice"))
+	})
+
+	It("rejects a forged Assertion stapled alongside a validly-signed one (signature wrapping)", func() {
+		signed := signAssertion(keyStore, buildAssertion("_original-assertion", "alice"))
+
+		// The forged assertion has no Signature of its own, and is placed
+		// ahead of the genuine one. A naive implementation that reads
+		// whichever <Assertion> it finds first (e.g. via xml.Unmarshal) would
+		// read the forged NameID ("admin"); ParseAndVerifyResponse must only
+		// trust the one that independently verifies.
+		forged := buildAssertion("_forged-assertion", "admin")
+
+		raw := encodedResponse(forged, signed)
+
+		assertion, err := saml.ParseAndVerifyResponse(raw, certPEM, audience, acsURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(assertion.NameID).To(Equal("alice"))
+	})
+
+	It("rejects an assertion signed by a different key", func() {
+		otherKeyStore := dsig.RandomKeyStoreForTest()
+		signed := signAssertion(otherKeyStore, buildAssertion("_original-assertion", "alice"))
+
+		_, err := saml.ParseAndVerifyResponse(encodedResponse(signed), certPEM, audience, acsURL)
+		Expect(err).To(Equal(saml.ErrNoSignedAssertion))
+	})
+
+	It("rejects a response whose Destination doesn't match the ACS URL", func() {
+		signed := signAssertion(keyStore, buildAssertion("_original-assertion", "alice"))
+
+		doc := etree.NewDocument()
+		response := doc.CreateElement("Response")
+		response.CreateAttr("Destination", "https://evil.example.com/callback")
+		response.AddChild(signed)
+		raw, err := doc.WriteToBytes()
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = saml.ParseAndVerifyResponse(base64.StdEncoding.EncodeToString(raw), certPEM, audience, acsURL)
+		Expect(err).To(Equal(saml.ErrDestinationMismatch))
+	})
+
+	It("rejects an assertion whose conditions have expired", func() {
+		assertion := buildAssertion("_original-assertion", "alice")
+		conditions := assertion.FindElement("Conditions")
+		conditions.RemoveAttr("NotOnOrAfter")
+		conditions.CreateAttr("NotOnOrAfter", time.Now().Add(-time.Minute).UTC().Format(time.RFC3339))
+
+		signed := signAssertion(keyStore, assertion)
+
+		_, err := saml.ParseAndVerifyResponse(encodedResponse(signed), certPEM, audience, acsURL)
+		Expect(err).To(Equal(saml.ErrConditionsNotMet))
+	})
+})
@@ -0,0 +1,13 @@
+package saml_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSaml(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SAML Provider Suite")
+}
@@ -0,0 +1,83 @@
+package saml
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/jessevdk/go-flags"
+
+	"github.com/concourse/atc/auth/provider"
+)
+
+func init() {
+	provider.RegisterNonOAuth(ProviderName, TeamProvider{})
+}
+
+type AuthConfig struct {
+	EntityID      string   `json:"entity_id"       long:"entity-id"       description:"SP entity ID to present to the IdP."`
+	ACSURL        string   `json:"acs_url"         long:"acs-url"         description:"Assertion consumer service URL the IdP should POST the response back to."`
+	IDPSSOURL     string   `json:"idp_sso_url"     long:"idp-sso-url"     description:"IdP single sign-on URL to redirect to."`
+	IDPCert       string   `json:"idp_cert"        long:"idp-cert"        description:"PEM-encoded IdP signing certificate used to verify assertions."`
+	AttributeName string   `json:"attribute_name,omitempty" long:"attribute-name" description:"Assertion attribute to match --saml-group against. Defaults to 'groups'."`
+	Users         []string `json:"users,omitempty" long:"user"  description:"NameID of a user allowed to log in."`
+	Groups        []string `json:"groups,omitempty" long:"group" description:"Attribute value allowed to log in, matched against --saml-attribute-name."`
+}
+
+func (config *AuthConfig) IsConfigured() bool {
+	return config.EntityID != "" || config.IDPSSOURL != ""
+}
+
+func (config *AuthConfig) Validate() error {
+	var errs *multierror.Error
+
+	if config.EntityID == "" || config.ACSURL == "" || config.IDPSSOURL == "" || config.IDPCert == "" {
+		errs = multierror.Append(errs, errors.New("must specify --saml-entity-id, --saml-acs-url, --saml-idp-sso-url and --saml-idp-cert to use SAML."))
+	}
+
+	if len(config.Users) == 0 && len(config.Groups) == 0 {
+		errs = multierror.Append(errs, errors.New("must specify --saml-user or --saml-group to use SAML."))
+	}
+
+	return errs.ErrorOrNil()
+}
+
+type TeamProvider struct{}
+
+func (TeamProvider) AddAuthGroup(group *flags.Group) provider.AuthConfig {
+	authFlags := &AuthConfig{}
+
+	authGroup, err := group.AddGroup("SAML Authentication", "", authFlags)
+	if err != nil {
+		panic(err)
+	}
+
+	authGroup.Namespace = "saml"
+
+	return authFlags
+}
+
+func (TeamProvider) UnmarshalConfig(config *json.RawMessage) (provider.AuthConfig, error) {
+	authFlags := &AuthConfig{}
+	if config != nil {
+		if err := json.Unmarshal(*config, authFlags); err != nil {
+			return nil, err
+		}
+	}
+
+	return authFlags, nil
+}
+
+func (TeamProvider) ProviderConstructor(config provider.AuthConfig) Provider {
+	authConfig := config.(*AuthConfig)
+
+	return NewProvider(
+		authConfig.EntityID,
+		authConfig.ACSURL,
+		authConfig.IDPSSOURL,
+		authConfig.IDPCert,
+		authConfig.AttributeName,
+		authConfig.Users,
+		authConfig.Groups,
+	)
+}
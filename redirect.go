@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// IsValidRedirect reports whether uri is safe to send a user to after a
+// successful OAuth callback. OAuthState.Redirect is attacker-controlled (it
+// round-trips through the state cookie), so a bare "follow it" is an
+// open-redirect vector: relative paths are always fine, but an absolute URL
+// must point at the request's own host or an operator-configured
+// whitelistDomains entry (an exact host, or a leading-dot suffix like
+// ".example.com" to allow any subdomain).
+func IsValidRedirect(uri string, requestHost string, whitelistDomains []string) bool {
+	if uri == "" {
+		return false
+	}
+
+	// Browsers (historically IE) treat a leading backslash the same as a
+	// leading slash, turning "/\evil.com" into the protocol-relative
+	// "//evil.com" after normalization, so reject backslashes outright.
+	if strings.ContainsAny(uri, "\\") {
+		return false
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+
+	if u.Scheme == "" && u.Host == "" {
+		// A relative path. "//evil.com" parses with an empty scheme but
+		// is interpreted by browsers as protocol-relative to evil.com, so
+		// it must not be treated as relative.
+		return strings.HasPrefix(uri, "/") && !strings.HasPrefix(uri, "//")
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return false
+	}
+
+	if strings.EqualFold(host, hostWithoutPort(requestHost)) {
+		return true
+	}
+
+	for _, allowed := range whitelistDomains {
+		if strings.HasPrefix(allowed, ".") {
+			if strings.HasSuffix(strings.ToLower(host), strings.ToLower(allowed)) {
+				return true
+			}
+			continue
+		}
+
+		if strings.EqualFold(host, allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hostWithoutPort(host string) string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return hostname
+	}
+
+	return host
+}
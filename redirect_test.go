@@ -0,0 +1,59 @@
+package auth_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/auth"
+)
+
+var _ = Describe("IsValidRedirect", func() {
+	It("allows relative paths", func() {
+		Expect(auth.IsValidRedirect("/", "atc.example.com", nil)).To(BeTrue())
+		Expect(auth.IsValidRedirect("/teams/main/pipelines/foo", "atc.example.com", nil)).To(BeTrue())
+	})
+
+	It("allows an absolute URL pointing at the request's own host", func() {
+		Expect(auth.IsValidRedirect("https://atc.example.com/foo", "atc.example.com", nil)).To(BeTrue())
+		Expect(auth.IsValidRedirect("https://atc.example.com:443/foo", "atc.example.com:443", nil)).To(BeTrue())
+	})
+
+	It("allows an absolute URL matching a whitelisted host", func() {
+		Expect(auth.IsValidRedirect("https://ci.example.com/foo", "atc.example.com", []string{"ci.example.com"})).To(BeTrue())
+	})
+
+	It("allows an absolute URL matching a whitelisted subdomain wildcard", func() {
+		Expect(auth.IsValidRedirect("https://sub.example.com/foo", "atc.example.com", []string{".example.com"})).To(BeTrue())
+		Expect(auth.IsValidRedirect("https://example.com/foo", "atc.example.com", []string{".example.com"})).To(BeFalse())
+	})
+
+	It("rejects an absolute URL pointing at an unknown host", func() {
+		Expect(auth.IsValidRedirect("https://evil.com/foo", "atc.example.com", nil)).To(BeFalse())
+	})
+
+	It("rejects protocol-relative URLs used to smuggle a foreign host", func() {
+		Expect(auth.IsValidRedirect("//evil.com", "atc.example.com", nil)).To(BeFalse())
+	})
+
+	It("rejects backslash tricks that browsers normalize into protocol-relative URLs", func() {
+		Expect(auth.IsValidRedirect(`/\evil.com`, "atc.example.com", nil)).To(BeFalse())
+		Expect(auth.IsValidRedirect(`/\/evil.com`, "atc.example.com", nil)).To(BeFalse())
+	})
+
+	It("rejects userinfo tricks where the real host is hidden after an @", func() {
+		Expect(auth.IsValidRedirect("https://atc.example.com@evil.com/foo", "atc.example.com", nil)).To(BeFalse())
+	})
+
+	It("rejects non-http(s) schemes", func() {
+		Expect(auth.IsValidRedirect("javascript:alert(1)", "atc.example.com", nil)).To(BeFalse())
+	})
+
+	It("handles IPv6 literal hosts", func() {
+		Expect(auth.IsValidRedirect("http://[::1]:8080/foo", "[::1]:8080", nil)).To(BeTrue())
+		Expect(auth.IsValidRedirect("http://[::1]:8080/foo", "atc.example.com", nil)).To(BeFalse())
+	})
+
+	It("rejects an empty redirect", func() {
+		Expect(auth.IsValidRedirect("", "atc.example.com", nil)).To(BeFalse())
+	})
+})
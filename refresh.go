@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/concourse/atc/auth/provider"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pivotal-golang/lager"
+)
+
+// RefreshWindow is how far ahead of a token's exp TokenRefresher attempts a
+// transparent refresh, so a session in active use never hits the hard
+// CookieAge expiry and forces the user back through the browser OAuth
+// dance.
+const RefreshWindow = 1 * time.Hour
+
+// TokenRefresher re-mints the ATC-Authorization cookie in place when it's
+// within RefreshWindow of expiring and was minted for a provider.Refresher.
+// It's meant to run on the request-validation path, ahead of whatever
+// consults UserContextReader.GetTeam, so that by the time a handler reads
+// the token it's already current. It consumes the refresh_token claim that
+// mintToken writes.
+type TokenRefresher struct {
+	PrivateKey *rsa.PrivateKey
+	Providers  provider.Providers
+}
+
+// RefreshIfNeeded is a no-op unless the ATC-Authorization cookie on r both
+// carries a refresh_token and is within RefreshWindow of expiring, in which
+// case it exchanges the refresh token via the originating provider's
+// Refresher and overwrites the cookie on w with a freshly signed,
+// freshly-expiring one. Failures (unknown/non-refreshing provider, refresh
+// exchange error) are logged and otherwise ignored: the existing cookie is
+// left in place, so the user simply re-authenticates once it actually
+// expires rather than the request failing outright.
+func (refresher TokenRefresher) RefreshIfNeeded(ctx context.Context, logger lager.Logger, w http.ResponseWriter, r *http.Request) {
+	cookieValue, err := ReadChunkedCookie(r, CookieName)
+	if err != nil {
+		return
+	}
+
+	rawToken := strings.TrimPrefix(cookieValue, "Bearer ")
+
+	parsed, _ := jwt.Parse(rawToken, keyFunc(refresher.PrivateKey))
+	if parsed == nil {
+		return
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return
+	}
+
+	expUnix, ok := claims["exp"].(float64)
+	if !ok {
+		return
+	}
+
+	if time.Unix(int64(expUnix), 0).After(time.Now().Add(RefreshWindow)) {
+		return
+	}
+
+	refreshToken, _ := claims["refresh_token"].(string)
+	if refreshToken == "" {
+		return
+	}
+
+	providerName, _ := claims["provider"].(string)
+
+	p, found := refresher.Providers[providerName]
+	if !found {
+		logger.Info("unknown-refresh-provider", lager.Data{"provider": providerName})
+		return
+	}
+
+	providerRefresher, ok := p.(provider.Refresher)
+	if !ok {
+		logger.Info("provider-does-not-support-refresh", lager.Data{"provider": providerName})
+		return
+	}
+
+	oldToken := &oauth2.Token{RefreshToken: refreshToken}
+	if idToken, ok := claims["id_token"].(string); ok {
+		oldToken = oldToken.WithExtra(map[string]interface{}{"id_token": idToken})
+	}
+
+	newToken, err := providerRefresher.Refresh(ctx, oldToken)
+	if err != nil {
+		logger.Error("failed-to-refresh-token", err)
+		return
+	}
+
+	scopes, _ := scopesFromClaims(claims)
+
+	newExp := time.Now().Add(CookieAge)
+
+	signedToken, err := mintToken(refresher.PrivateKey, providerName, newToken, newExp, scopes)
+	if err != nil {
+		logger.Error("failed-to-sign-refreshed-token", err)
+		return
+	}
+
+	WriteChunkedCookie(w, CookieName, "Bearer "+signedToken, "/", newExp)
+}
@@ -0,0 +1,13 @@
+package auth
+
+import "net/http"
+
+//go:generate counterfeiter . Rejector
+
+// Rejector writes the response for a request CheckAuthorizationHandler has
+// decided not to let through, so callers that want an HTML error page, a
+// JSON body, or a bare status code can each supply their own.
+type Rejector interface {
+	Unauthorized(w http.ResponseWriter, r *http.Request)
+	Forbidden(w http.ResponseWriter, r *http.Request)
+}
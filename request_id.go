@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/context"
+)
+
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKeyType int
+
+const requestIDKey requestIDKeyType = iota
+
+// RequestIDMiddleware stamps every request with a correlation ID (trusting
+// an incoming X-Request-Id if the caller already set one, e.g. a load
+// balancer or another hop in the chain) so an OAuth login can be traced
+// across the ATC, the identity provider, and any verifier calls made along
+// the way. The ID is echoed back on the response and stashed on the
+// request's gorilla/context alongside teamNameKey so GetRequestID can
+// recover it downstream.
+func RequestIDMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = generateRequestID()
+		}
+
+		context.Set(r, requestIDKey, requestID)
+		w.Header().Set(RequestIDHeader, requestID)
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func GetRequestID(r *http.Request) string {
+	requestID, ok := context.GetOk(r, requestIDKey)
+	if !ok {
+		return ""
+	}
+
+	return requestID.(string)
+}
+
+func generateRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:16])
+}
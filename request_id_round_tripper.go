@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// requestIDRoundTripper injects the correlation ID for the in-flight
+// request onto outbound calls a Provider makes to GitHub, an OIDC
+// userinfo/JWKS endpoint, or any other identity provider, so the ID shows
+// up in their access logs too.
+type requestIDRoundTripper struct {
+	requestID string
+	transport http.RoundTripper
+}
+
+func (rt requestIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt.requestID != "" {
+		req = cloneRequestWithHeader(req, RequestIDHeader, rt.requestID)
+	}
+
+	transport := rt.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return transport.RoundTrip(req)
+}
+
+func cloneRequestWithHeader(req *http.Request, header string, value string) *http.Request {
+	clone := new(http.Request)
+	*clone = *req
+	clone.Header = req.Header.Clone()
+	clone.Header.Set(header, value)
+	return clone
+}
+
+// WrapClientWithRequestID returns a client that behaves like httpClient but
+// injects the given request ID into every outbound request, so a Provider's
+// HTTP client keeps propagating it for the lifetime of the login.
+//
+// httpClient.Transport is often an *oauth2.Transport (e.g. the client a
+// Provider hands back from p.Client(ctx, token)), and providers such as
+// oidc and keycloak recover the exchanged token by type-asserting on that
+// concrete transport. Swapping it out for requestIDRoundTripper outright
+// would hide the token from them, so when the existing transport is an
+// *oauth2.Transport, splice the request-ID round tripper into its Base
+// instead of replacing it.
+func WrapClientWithRequestID(httpClient *http.Client, requestID string) *http.Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	wrapped := *httpClient
+
+	if oauthTransport, ok := httpClient.Transport.(*oauth2.Transport); ok {
+		withRequestID := *oauthTransport
+		withRequestID.Base = requestIDRoundTripper{
+			requestID: requestID,
+			transport: oauthTransport.Base,
+		}
+		wrapped.Transport = &withRequestID
+	} else {
+		wrapped.Transport = requestIDRoundTripper{
+			requestID: requestID,
+			transport: httpClient.Transport,
+		}
+	}
+
+	return &wrapped
+}
@@ -0,0 +1,108 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"golang.org/x/oauth2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/auth"
+)
+
+var _ = Describe("WrapClientWithRequestID", func() {
+	var (
+		receivedHeader string
+		server         *httptest.Server
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedHeader = r.Header.Get(auth.RequestIDHeader)
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("stamps the given request ID onto every outbound request", func() {
+		client := auth.WrapClientWithRequestID(&http.Client{}, "request-id-123")
+
+		resp, err := client.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+
+		Expect(receivedHeader).To(Equal("request-id-123"))
+	})
+
+	It("does not set the header when no request ID is given", func() {
+		client := auth.WrapClientWithRequestID(&http.Client{}, "")
+
+		resp, err := client.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+
+		Expect(receivedHeader).To(BeEmpty())
+	})
+
+	It("preserves a pre-existing transport instead of discarding it", func() {
+		var innerTransportUsed bool
+
+		innerTransport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			innerTransportUsed = true
+			return http.DefaultTransport.RoundTrip(req)
+		})
+
+		client := auth.WrapClientWithRequestID(&http.Client{Transport: innerTransport}, "request-id-456")
+
+		resp, err := client.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+
+		Expect(innerTransportUsed).To(BeTrue())
+		Expect(receivedHeader).To(Equal("request-id-456"))
+	})
+
+	It("treats a nil client as a default client rather than panicking", func() {
+		client := auth.WrapClientWithRequestID(nil, "request-id-789")
+
+		resp, err := client.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+
+		Expect(receivedHeader).To(Equal("request-id-789"))
+	})
+
+	It("keeps the outer transport an *oauth2.Transport so providers can still recover the token", func() {
+		tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "the-token"})
+
+		oauthClient := &http.Client{
+			Transport: &oauth2.Transport{Source: tokenSource},
+		}
+
+		client := auth.WrapClientWithRequestID(oauthClient, "request-id-abc")
+
+		oauthTransport, ok := client.Transport.(*oauth2.Transport)
+		Expect(ok).To(BeTrue(), "wrapped transport should still type-assert as *oauth2.Transport")
+
+		token, err := oauthTransport.Source.Token()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("the-token"))
+
+		resp, err := client.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+
+		Expect(receivedHeader).To(Equal("request-id-abc"))
+	})
+})
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,12 @@
+package auth
+
+import "net/http"
+
+//go:generate counterfeiter . Validator
+
+// Validator decides whether a request carries valid credentials, without
+// caring what kind - basic auth, a bearer token, a client cert, and so on
+// are all just Validators that WrapHandler can try.
+type Validator interface {
+	IsAuthenticated(r *http.Request) bool
+}
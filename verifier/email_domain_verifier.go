@@ -0,0 +1,53 @@
+package verifier
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// EmailDomainVerifier allows any user whose userinfo "email" claim belongs
+// to one of the configured domains, e.g. letting an operator whitelist
+// "example.com" instead of enumerating every user.
+type EmailDomainVerifier struct {
+	userInfoURL    string
+	allowedDomains []string
+}
+
+func NewEmailDomainVerifier(userInfoURL string, allowedDomains []string) EmailDomainVerifier {
+	return EmailDomainVerifier{
+		userInfoURL:    userInfoURL,
+		allowedDomains: allowedDomains,
+	}
+}
+
+func (verifier EmailDomainVerifier) Verify(logger lager.Logger, httpClient *http.Client) (bool, error) {
+	userInfo, err := fetchUserInfo(httpClient, verifier.userInfoURL)
+	if err != nil {
+		logger.Error("failed-to-fetch-userinfo", err)
+		return false, err
+	}
+
+	email, _ := userInfo["email"].(string)
+
+	parts := strings.SplitN(email, "@", 2)
+	if len(parts) != 2 {
+		return false, nil
+	}
+
+	domain := parts[1]
+
+	for _, allowed := range verifier.allowedDomains {
+		if strings.EqualFold(domain, allowed) {
+			return true, nil
+		}
+	}
+
+	logger.Info("not-validated-email-domain", lager.Data{
+		"have": domain,
+		"want": verifier.allowedDomains,
+	})
+
+	return false, nil
+}
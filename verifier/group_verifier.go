@@ -0,0 +1,52 @@
+package verifier
+
+import (
+	"net/http"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// GroupVerifier allows any user whose userinfo "groups" claim (or another
+// configured claim) intersects with the configured allow-list.
+type GroupVerifier struct {
+	userInfoURL   string
+	groupsClaim   string
+	allowedGroups []string
+}
+
+func NewGroupVerifier(userInfoURL string, groupsClaim string, allowedGroups []string) GroupVerifier {
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+
+	return GroupVerifier{
+		userInfoURL:   userInfoURL,
+		groupsClaim:   groupsClaim,
+		allowedGroups: allowedGroups,
+	}
+}
+
+func (verifier GroupVerifier) Verify(logger lager.Logger, httpClient *http.Client) (bool, error) {
+	userInfo, err := fetchUserInfo(httpClient, verifier.userInfoURL)
+	if err != nil {
+		logger.Error("failed-to-fetch-userinfo", err)
+		return false, err
+	}
+
+	groups := stringSlice(lookup(userInfo, verifier.groupsClaim))
+
+	for _, group := range groups {
+		for _, allowed := range verifier.allowedGroups {
+			if group == allowed {
+				return true, nil
+			}
+		}
+	}
+
+	logger.Info("not-validated-group", lager.Data{
+		"have": groups,
+		"want": verifier.allowedGroups,
+	})
+
+	return false, nil
+}
@@ -0,0 +1,70 @@
+package verifier_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/lager/lagertest"
+
+	"github.com/concourse/atc/auth/verifier"
+)
+
+var _ = Describe("GroupVerifier", func() {
+	var (
+		userInfoServer *httptest.Server
+		userInfo       map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		userInfoServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(userInfo)
+		}))
+	})
+
+	AfterEach(func() {
+		userInfoServer.Close()
+	})
+
+	Describe("Verify", func() {
+		It("matches a top-level groups claim", func() {
+			userInfo = map[string]interface{}{
+				"groups": []interface{}{"some-other-group", "some-group"},
+			}
+
+			v := verifier.NewGroupVerifier(userInfoServer.URL, "groups", []string{"some-group"})
+
+			verified, err := v.Verify(lagertest.NewTestLogger("test"), userInfoServer.Client())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(verified).To(BeTrue())
+		})
+
+		It("matches a nested claim addressed via a JSON pointer", func() {
+			userInfo = map[string]interface{}{
+				"realm_access": map[string]interface{}{
+					"roles": []interface{}{"some-role"},
+				},
+			}
+
+			v := verifier.NewGroupVerifier(userInfoServer.URL, "realm_access/roles", []string{"some-role"})
+
+			verified, err := v.Verify(lagertest.NewTestLogger("test"), userInfoServer.Client())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(verified).To(BeTrue())
+		})
+
+		It("fails when none of the allowed groups are present", func() {
+			userInfo = map[string]interface{}{
+				"groups": []interface{}{"some-other-group"},
+			}
+
+			v := verifier.NewGroupVerifier(userInfoServer.URL, "groups", []string{"some-group"})
+
+			verified, err := v.Verify(lagertest.NewTestLogger("test"), userInfoServer.Client())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(verified).To(BeFalse())
+		})
+	})
+})
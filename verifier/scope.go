@@ -0,0 +1,30 @@
+package verifier
+
+import (
+	"net/http"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// Scope grants a role on a resource, e.g. {Resource: "team:main", Role:
+// "member"} or {Resource: "team:main/pipeline:foo", Role: "read"}.
+type Scope struct {
+	Resource string `json:"resource"`
+	Role     string `json:"role"`
+}
+
+func (s Scope) String() string {
+	return s.Resource + ":" + s.Role
+}
+
+//go:generate counterfeiter . ScopeVerifier
+
+// ScopeVerifier is implemented by verifiers that can grant fine-grained
+// scopes rather than only a pass/fail answer - a group verifier backed by
+// a realm with both auditor and developer roles, say, granting "read" for
+// one and "member" for the other. Verifiers that don't support this
+// (most of them) simply don't implement it; callers fall back to the
+// unscoped, full-team access a plain Verifier already grants.
+type ScopeVerifier interface {
+	VerifyScopes(lager.Logger, *http.Client) ([]Scope, error)
+}
@@ -0,0 +1,50 @@
+package verifier
+
+import (
+	"net/http"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// UserVerifier allows any user whose userinfo "email" (or another
+// configured claim) is in an explicit allow-list.
+type UserVerifier struct {
+	userInfoURL  string
+	claim        string
+	allowedUsers []string
+}
+
+func NewUserVerifier(userInfoURL string, claim string, allowedUsers []string) UserVerifier {
+	if claim == "" {
+		claim = "email"
+	}
+
+	return UserVerifier{
+		userInfoURL:  userInfoURL,
+		claim:        claim,
+		allowedUsers: allowedUsers,
+	}
+}
+
+func (verifier UserVerifier) Verify(logger lager.Logger, httpClient *http.Client) (bool, error) {
+	userInfo, err := fetchUserInfo(httpClient, verifier.userInfoURL)
+	if err != nil {
+		logger.Error("failed-to-fetch-userinfo", err)
+		return false, err
+	}
+
+	user, _ := lookup(userInfo, verifier.claim).(string)
+
+	for _, allowed := range verifier.allowedUsers {
+		if user == allowed {
+			return true, nil
+		}
+	}
+
+	logger.Info("not-validated-user", lager.Data{
+		"have": user,
+		"want": verifier.allowedUsers,
+	})
+
+	return false, nil
+}
@@ -0,0 +1,71 @@
+package verifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// fetchUserInfo calls an OAuth2 userinfo endpoint with an already
+// token-authenticated client and decodes the JSON response, so verifiers
+// built against arbitrary OAuth2 providers don't each need their own HTTP
+// plumbing.
+func fetchUserInfo(client *http.Client, userInfoURL string) (map[string]interface{}, error) {
+	response, err := client.Get(userInfoURL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code from userinfo URL: %d", response.StatusCode)
+	}
+
+	var userInfo map[string]interface{}
+	if err := json.NewDecoder(response.Body).Decode(&userInfo); err != nil {
+		return nil, err
+	}
+
+	return userInfo, nil
+}
+
+// lookup resolves key as a JSON pointer (RFC 6901, without the leading
+// slash) against userInfo, e.g. "resource_access/concourse/roles" or
+// "email". A bare key with no slashes is just a top-level map lookup, which
+// covers the common case without forcing every operator to learn pointer
+// syntax. Only object members are walked: array indices and the ~0/~1
+// escapes are not supported.
+func lookup(userInfo map[string]interface{}, key string) interface{} {
+	var current interface{} = userInfo
+
+	for _, segment := range strings.Split(key, "/") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		current, ok = m[segment]
+		if !ok {
+			return nil
+		}
+	}
+
+	return current
+}
+
+func stringSlice(values interface{}) []string {
+	raw, ok := values.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	strs := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+
+	return strs
+}
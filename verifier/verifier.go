@@ -0,0 +1,39 @@
+package verifier
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pivotal-golang/lager"
+)
+
+type Verifier interface {
+	Verify(lager.Logger, *http.Client) (bool, error)
+}
+
+// Basket runs a set of verifiers and succeeds as soon as one of them does,
+// mirroring github.VerifierBasket for providers outside the github package.
+type Basket struct {
+	verifiers []Verifier
+}
+
+func NewBasket(verifiers ...Verifier) Basket {
+	return Basket{verifiers: verifiers}
+}
+
+func (vb Basket) Verify(logger lager.Logger, client *http.Client) (bool, error) {
+	var errs error
+
+	for _, v := range vb.verifiers {
+		verified, err := v.Verify(logger, client)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		if verified {
+			return true, nil
+		}
+	}
+
+	return false, errs
+}
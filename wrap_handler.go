@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gorilla/context"
+)
+
+type key int
+
+const (
+	authenticatedKey key = iota
+	teamNameKey
+	teamIDKey
+	isAdminKey
+	scopesKey
+)
+
+// WrapHandler decorates handler so that, for every request, it first asks
+// validator whether the request is authenticated and userContextReader
+// what team and scopes (if any) the request's credentials grant, and
+// stashes all of it on the request's gorilla/context - alongside
+// requestIDKey (request_id.go) - so downstream handlers can recover it via
+// IsAuthenticated, GetTeam, and GetScopes without depending on the
+// Validator/UserContextReader themselves.
+func WrapHandler(
+	handler http.Handler,
+	validator Validator,
+	userContextReader UserContextReader,
+) http.Handler {
+	return wrappedHandler{
+		handler:           handler,
+		validator:         validator,
+		userContextReader: userContextReader,
+	}
+}
+
+type wrappedHandler struct {
+	handler           http.Handler
+	validator         Validator
+	userContextReader UserContextReader
+}
+
+func (h wrappedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	context.Set(r, authenticatedKey, h.validator.IsAuthenticated(r))
+
+	if teamName, teamID, isAdmin, found := h.userContextReader.GetTeam(r); found {
+		context.Set(r, teamNameKey, teamName)
+		context.Set(r, teamIDKey, teamID)
+		context.Set(r, isAdminKey, isAdmin)
+	}
+
+	if scopes, found := h.userContextReader.GetScopes(r); found {
+		context.Set(r, scopesKey, scopes)
+	}
+
+	h.handler.ServeHTTP(w, r)
+}
+
+// IsAuthenticated reports whether WrapHandler's validator found valid
+// credentials on this request.
+func IsAuthenticated(r *http.Request) bool {
+	authenticated, ok := context.GetOk(r, authenticatedKey)
+	if !ok {
+		return false
+	}
+
+	return authenticated.(bool)
+}